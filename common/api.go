@@ -2,8 +2,14 @@ package common
 
 import "context"
 
+// ListAPI interface of the read-only resources
+type ListAPI[T any] interface {
+	List(ctx context.Context) ([]T, error)
+}
+
 // API interface
 type API[T any, I comparable] interface {
+	ListAPI[T]
 	Create(ctx context.Context, params T) (*I, error)
 	Read(ctx context.Context, id I) (*T, error)
 	Update(ctx context.Context, params T) (*T, error)
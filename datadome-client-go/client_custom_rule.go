@@ -82,8 +82,8 @@ func (c *ClientCustomRule) doRequest(req *http.Request, httpResponse *HttpRespon
 	return httpResponse, err
 }
 
-// Read custom rule list from the API management
-func (c *ClientCustomRule) Read(ctx context.Context, id int) (*CustomRule, error) {
+// List all the custom rules from the API management
+func (c *ClientCustomRule) List(ctx context.Context) ([]CustomRule, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", c.HostURL, nil)
 	if err != nil {
 		return nil, err
@@ -100,8 +100,18 @@ func (c *ClientCustomRule) Read(ctx context.Context, id int) (*CustomRule, error
 		return nil, fmt.Errorf("response status is %d", resp.Status)
 	}
 
+	return customRules.CustomRules, nil
+}
+
+// Read custom rule from the list returned by the API management
+func (c *ClientCustomRule) Read(ctx context.Context, id int) (*CustomRule, error) {
+	customRules, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	customRule := &CustomRule{}
-	for _, v := range customRules.CustomRules {
+	for _, v := range customRules {
 		if *v.ID == id {
 			customRule = &v
 		}
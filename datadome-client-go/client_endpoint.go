@@ -39,8 +39,8 @@ func NewClientEndpoint(host, password *string) (*ClientEndpoint, error) {
 	return &c, nil
 }
 
-// doRequest on the DataDome API with given http.Request and HttpResponse
-func (c *ClientEndpoint) doRequest(req *http.Request, endpoint *Endpoint) error {
+// doRequest on the DataDome API with given http.Request and decode the body into out
+func (c *ClientEndpoint) doRequest(req *http.Request, out interface{}) error {
 	// Add apikey as a header on each request for authentication
 	req.Header.Set("x-api-key", c.Token)
 
@@ -66,8 +66,8 @@ func (c *ClientEndpoint) doRequest(req *http.Request, endpoint *Endpoint) error
 
 	log.Printf("[DEBUG] %s\n", body)
 
-	if endpoint != nil {
-		err = json.Unmarshal(body, endpoint)
+	if out != nil {
+		err = json.Unmarshal(body, out)
 		if err != nil {
 			return err
 		}
@@ -76,6 +76,23 @@ func (c *ClientEndpoint) doRequest(req *http.Request, endpoint *Endpoint) error
 	return err
 }
 
+// List all the endpoints from the API management
+func (c *ClientEndpoint) List(ctx context.Context) ([]Endpoint, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.HostURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := []Endpoint{}
+
+	err = c.doRequest(req, &endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
 // Read endpoint information by its ID from the API management
 func (c *ClientEndpoint) Read(ctx context.Context, id string) (*Endpoint, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/%s", c.HostURL, id), nil)
@@ -4,12 +4,14 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/google/uuid"
 )
 
 // MockClientCustomRule structure for test purposes on the custom rules
 type MockClientCustomRule struct {
+	ListFunc   func(ctx context.Context) ([]CustomRule, error)
 	CreateFunc func(ctx context.Context, params CustomRule) (*int, error)
 	ReadFunc   func(ctx context.Context, id int) (*CustomRule, error)
 	UpdateFunc func(ctx context.Context, params CustomRule) (*CustomRule, error)
@@ -25,6 +27,21 @@ func NewMockClientCustomRule() *MockClientCustomRule {
 	}
 }
 
+// List mock method
+func (m *MockClientCustomRule) List(ctx context.Context) ([]CustomRule, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+
+	values := make([]CustomRule, 0, len(m.resources))
+	for _, v := range m.resources {
+		values = append(values, *v)
+	}
+	sort.Slice(values, func(i, j int) bool { return *values[i].ID < *values[j].ID })
+
+	return values, nil
+}
+
 // Create mock method
 func (m *MockClientCustomRule) Create(ctx context.Context, params CustomRule) (*int, error) {
 	if m.CreateFunc != nil {
@@ -89,6 +106,7 @@ func (m *MockClientCustomRule) Delete(ctx context.Context, id int) error {
 
 // MockClientEndpoint structure for test purposes on the endpoints
 type MockClientEndpoint struct {
+	ListFunc   func(ctx context.Context) ([]Endpoint, error)
 	CreateFunc func(ctx context.Context, params Endpoint) (*string, error)
 	ReadFunc   func(ctx context.Context, id string) (*Endpoint, error)
 	UpdateFunc func(ctx context.Context, params Endpoint) (*Endpoint, error)
@@ -104,6 +122,21 @@ func NewMockClientEndpoint() *MockClientEndpoint {
 	}
 }
 
+// List mock method
+func (m *MockClientEndpoint) List(ctx context.Context) ([]Endpoint, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+
+	values := make([]Endpoint, 0, len(m.resources))
+	for _, v := range m.resources {
+		values = append(values, *v)
+	}
+	sort.Slice(values, func(i, j int) bool { return *values[i].ID < *values[j].ID })
+
+	return values, nil
+}
+
 // Create mock method
 func (m *MockClientEndpoint) Create(ctx context.Context, params Endpoint) (*string, error) {
 	if m.CreateFunc != nil {
@@ -166,3 +199,29 @@ func (m *MockClientEndpoint) Delete(ctx context.Context, id string) error {
 	delete(m.resources, id)
 	return nil
 }
+
+// MockClientVerifiedBot structure for test purposes on the verified bots
+type MockClientVerifiedBot struct {
+	ListFunc func(ctx context.Context) ([]VerifiedBot, error)
+
+	resources []VerifiedBot
+}
+
+// NewMockClientVerifiedBot returns a new MockClient listing the given verified bots
+func NewMockClientVerifiedBot(verifiedBots ...VerifiedBot) *MockClientVerifiedBot {
+	return &MockClientVerifiedBot{
+		resources: verifiedBots,
+	}
+}
+
+// List mock method
+func (m *MockClientVerifiedBot) List(ctx context.Context) ([]VerifiedBot, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+
+	values := make([]VerifiedBot, len(m.resources))
+	copy(values, m.resources)
+
+	return values, nil
+}
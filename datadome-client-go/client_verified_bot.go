@@ -0,0 +1,96 @@
+package datadome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// HostURLVerifiedBot default datadome dashboard URL
+const HostURLVerifiedBot string = "https://customer-api.datadome.co/1.1/protection/verified-bots"
+
+// ClientVerifiedBot to perform request on DataDome's API
+type ClientVerifiedBot struct {
+	HostURL    string
+	HTTPClient *http.Client
+	Token      string
+}
+
+// NewClientVerifiedBot creates a new client instance for Verified Bots using the specified host and password parameters
+func NewClientVerifiedBot(host, password *string) (*ClientVerifiedBot, error) {
+	c := ClientVerifiedBot{
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		HostURL:    HostURLVerifiedBot,
+	}
+
+	if host != nil {
+		c.HostURL = *host
+	}
+
+	if password != nil {
+		c.Token = *password
+	}
+
+	return &c, nil
+}
+
+// doRequest on the DataDome API with given http.Request and HttpResponse
+func (c *ClientVerifiedBot) doRequest(req *http.Request, httpResponse *HttpResponse) error {
+	// Add apikey as a header on each request for authentication
+	req.Header.Set("x-api-key", c.Token)
+
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = res.Body.Close()
+		if err != nil {
+			log.Printf("failed to close body: %v", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("status: %d, body: %s", res.StatusCode, body)
+	}
+
+	log.Printf("[DEBUG] %s\n", body)
+
+	err = json.Unmarshal(body, httpResponse)
+	if err != nil {
+		return err
+	}
+
+	if httpResponse.Status < 200 || httpResponse.Status > 299 {
+		return fmt.Errorf("status: %d, body: %s", httpResponse.Status, httpResponse.Errors)
+	}
+
+	return nil
+}
+
+// List all the Verified Bots and AI Agents from the API management
+func (c *ClientVerifiedBot) List(ctx context.Context) ([]VerifiedBot, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.HostURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedBots := &VerifiedBots{}
+	resp := &HttpResponse{Data: verifiedBots}
+
+	err = c.doRequest(req, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return verifiedBots.VerifiedBots, nil
+}
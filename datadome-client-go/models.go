@@ -59,6 +59,19 @@ func (o OverriddenBot) MarshalJSON() ([]byte, error) {
 	return json.Marshal(o.UUID)
 }
 
+// VerifiedBots structure containing a slice of VerifiedBot
+type VerifiedBots struct {
+	VerifiedBots []VerifiedBot `json:"verified_bots"`
+}
+
+// VerifiedBot structure containing the information of a Verified Bot or AI Agent,
+// whose UUID can be used as the overridden bot of a custom rule
+type VerifiedBot struct {
+	UUID     string `json:"uuid"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
 // PolicyOptions holds an optional rate-limit or time-box policy for a custom rule.
 // At most one of TimeBox or RateLimit may be set.
 // Only valid when the rule response is "allow" or "intent_based".
@@ -102,3 +102,22 @@ func TestCustomRuleNilOverriddenBot(t *testing.T) {
 		t.Error("overridden_bot should be absent when nil, but was present")
 	}
 }
+
+// TestVerifiedBotsUnmarshalJSON verifies that the verified bots listed in an API
+// response are decoded into the data of the HttpResponse.
+func TestVerifiedBotsUnmarshalJSON(t *testing.T) {
+	payload := `{"data":{"verified_bots":[{"uuid":"550e8400-e29b-41d4-a716-446655440000","name":"Googlebot","category":"Search Engine"}]},"status":200}`
+
+	verifiedBots := &VerifiedBots{}
+	resp := &HttpResponse{Data: verifiedBots}
+	if err := json.Unmarshal([]byte(payload), resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(verifiedBots.VerifiedBots) != 1 {
+		t.Fatalf("got %d verified bots, want 1", len(verifiedBots.VerifiedBots))
+	}
+	want := VerifiedBot{UUID: "550e8400-e29b-41d4-a716-446655440000", Name: "Googlebot", Category: "Search Engine"}
+	if verifiedBots.VerifiedBots[0] != want {
+		t.Errorf("VerifiedBot = %+v, want %+v", verifiedBots.VerifiedBots[0], want)
+	}
+}
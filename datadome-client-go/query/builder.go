@@ -0,0 +1,117 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldBuilder builds the terms of a field
+type FieldBuilder struct {
+	name string
+}
+
+// Field returns a builder of the terms of the given field, such as "ip", "url" or "countrycode"
+func Field(name string) FieldBuilder {
+	return FieldBuilder{name: name}
+}
+
+// Header returns a builder of the terms of the given request header, the "headers.<name>" field in lower case
+func Header(name string) FieldBuilder {
+	return Field("headers." + strings.ToLower(name))
+}
+
+// Equals returns a term matching when the field is equal to any of the values
+func (f FieldBuilder) Equals(values ...string) Term {
+	return f.term(values, Literal)
+}
+
+// In returns a term matching when the field is any of the values, such as IP addresses and CIDR ranges.
+// It is an alias of Equals.
+func (f FieldBuilder) In(values ...string) Term {
+	return f.Equals(values...)
+}
+
+// Contains returns a term matching when the field contains any of the values
+func (f FieldBuilder) Contains(values ...string) Term {
+	return f.term(values, func(value string) Value {
+		return Value{Raw: "*" + Escape(value) + "*"}
+	})
+}
+
+// StartsWith returns a term matching when the field starts with any of the values
+func (f FieldBuilder) StartsWith(values ...string) Term {
+	return f.term(values, func(value string) Value {
+		return Value{Raw: Escape(value) + "*"}
+	})
+}
+
+// EndsWith returns a term matching when the field ends with any of the values
+func (f FieldBuilder) EndsWith(values ...string) Term {
+	return f.term(values, func(value string) Value {
+		return Value{Raw: "*" + Escape(value)}
+	})
+}
+
+// Matches returns a term matching when the field matches any of the patterns, in which "*" are wildcards
+func (f FieldBuilder) Matches(patterns ...string) Term {
+	return f.term(patterns, Pattern)
+}
+
+func (f FieldBuilder) term(values []string, value func(string) Value) Term {
+	term := Term{Field: f.name, Values: make([]Value, len(values))}
+	for i, v := range values {
+		term.Values[i] = value(v)
+	}
+	return term
+}
+
+// Build returns the representation of an expression, or an error when the expression cannot be parsed back,
+// such as a term built without values, or an AND or OR without operands
+func Build(expr Expr) (string, error) {
+	if err := check(expr); err != nil {
+		return "", err
+	}
+	return expr.String(), nil
+}
+
+func check(expr Expr) error {
+	switch e := expr.(type) {
+	case And:
+		return checkOperands(e.Operands, "AND")
+	case Or:
+		return checkOperands(e.Operands, "OR")
+	case Not:
+		if e.Operand == nil {
+			return fmt.Errorf("NOT without operand")
+		}
+		return check(e.Operand)
+	case Term:
+		if e.Field == "" {
+			return fmt.Errorf("term without field")
+		}
+		if len(e.Values) == 0 {
+			return fmt.Errorf("no value for field %q", e.Field)
+		}
+		for _, v := range e.Values {
+			if v.String() == "" {
+				return fmt.Errorf("empty value for field %q", e.Field)
+			}
+		}
+		return nil
+	case nil:
+		return fmt.Errorf("empty query")
+	}
+	return nil
+}
+
+func checkOperands(operands []Expr, operator string) error {
+	if len(operands) == 0 {
+		return fmt.Errorf("%s without operands", operator)
+	}
+	for _, operand := range operands {
+		if err := check(operand); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     Expr
+		expected string
+	}{
+		{
+			name:     "in",
+			expr:     Field("ip").In("192.168.0.0/24", "10.0.0.1"),
+			expected: "ip:(192.168.0.0/24 OR 10.0.0.1)",
+		},
+		{
+			name:     "equals with special characters",
+			expr:     Field("url").Equals("/login (old)", "NOT"),
+			expected: `url:("/login (old)" OR "NOT")`,
+		},
+		{
+			name:     "contains a header",
+			expr:     Field("ip").In("10.0.0.1").And(Header("User-Agent").Contains("python requests")),
+			expected: `ip:10.0.0.1 AND headers.user-agent:*python\ requests*`,
+		},
+		{
+			name:     "starts and ends with",
+			expr:     Field("url").StartsWith("/api/").Or(Field("url").EndsWith(".php")),
+			expected: `url:/api/* OR url:*.php`,
+		},
+		{
+			name:     "matches",
+			expr:     Field("url").Matches("/api/*/login"),
+			expected: "url:/api/*/login",
+		},
+		{
+			name:     "nested groups",
+			expr:     Field("a").Equals("1").Or(Field("b").Equals("2")).And(Field("c").Equals("3").Not()),
+			expected: "(a:1 OR b:2) AND NOT c:3",
+		},
+		{
+			name:     "flattening",
+			expr:     Field("a").Equals("1").And(Field("b").Equals("2")).And(Field("c").Equals("3")),
+			expected: "a:1 AND b:2 AND c:3",
+		},
+		{
+			name:     "double negation",
+			expr:     Field("a").Equals("1").Not().Not(),
+			expected: "a:1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := test.expr.String()
+			if got != test.expected {
+				t.Fatalf("String() = %s, want %s", got, test.expected)
+			}
+
+			parsed, err := Parse(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, test.expr) {
+				t.Errorf("Parse(%q) = %#v, want %#v", got, parsed, test.expr)
+			}
+		})
+	}
+}
+
+func TestParse_Edit(t *testing.T) {
+	expr, err := Parse(`ip:(1.1.1.1 OR 2.2.2.2) AND url:/login`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	and := expr.(And)
+	ips := and.Operands[0].(Term)
+	ips.Values = append(ips.Values, Literal("3.3.3.3"))
+	and.Operands[0] = ips
+
+	want := `ip:(1.1.1.1 OR 2.2.2.2 OR 3.3.3.3) AND url:/login`
+	if got := and.String(); got != want {
+		t.Errorf("String() = %s, want %s", got, want)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	got, err := Build(Field("ip").In("10.0.0.1").And(Field("url").StartsWith("/api/")))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "ip:10.0.0.1 AND url:/api/*"; got != want {
+		t.Errorf("Build() = %s, want %s", got, want)
+	}
+
+	tests := map[string]struct {
+		expr     Expr
+		expected string
+	}{
+		"no values":        {expr: Field("ip").In(), expected: `no value for field "ip"`},
+		"no values nested": {expr: Field("url").Equals("/login").And(Field("ip").Equals().Not()), expected: `no value for field "ip"`},
+		"empty pattern":    {expr: Field("url").Matches(""), expected: `empty value for field "url"`},
+		"no field":         {expr: Field("").Equals("1"), expected: "term without field"},
+		"no operands":      {expr: AllOf(), expected: "AND without operands"},
+		"nil":              {expr: nil, expected: "empty query"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Build(test.expr)
+			if err == nil || err.Error() != test.expected {
+				t.Errorf("Build() error = %v, want %s", err, test.expected)
+			}
+		})
+	}
+}
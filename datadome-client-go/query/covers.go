@@ -0,0 +1,147 @@
+package query
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// Covers reports whether the expression a matches all the requests matched by the expression b.
+//
+// The check only relies on the structure of the expressions: it never reports a false positive,
+// but it may miss expressions which are equivalent in practice. Besides identical terms,
+// a value with wildcards covers the literal values it matches, and a CIDR range of the ip field
+// covers the addresses and ranges it contains.
+func Covers(a, b Expr) bool {
+	if a.String() == b.String() {
+		return true
+	}
+
+	// b is split first, so each of its alternatives can be covered by a different operand of a
+	switch b := b.(type) {
+	case Or:
+		for _, operand := range b.Operands {
+			if !Covers(a, operand) {
+				return false
+			}
+		}
+		return true
+	case Term:
+		if len(b.Values) > 1 {
+			for _, value := range b.Values {
+				if !Covers(a, Term{Field: b.Field, Values: []Value{value}}) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+
+	switch a := a.(type) {
+	case And:
+		for _, operand := range a.Operands {
+			if !Covers(operand, b) {
+				return false
+			}
+		}
+		return true
+	case Or:
+		for _, operand := range a.Operands {
+			if Covers(operand, b) {
+				return true
+			}
+		}
+	}
+
+	switch b := b.(type) {
+	case And:
+		for _, operand := range b.Operands {
+			if Covers(a, operand) {
+				return true
+			}
+		}
+		return false
+	case Not:
+		if a, ok := a.(Not); ok {
+			return Covers(b.Operand, a.Operand)
+		}
+		return false
+	case Term:
+		a, ok := a.(Term)
+		if !ok || a.Field != b.Field {
+			return false
+		}
+		for _, value := range a.Values {
+			if coversValue(a.Field, value, b.Values[0]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// coversValue reports whether the value a of a field matches all the requests matched by the value b
+func coversValue(field string, a, b Value) bool {
+	if a.String() == b.String() || (!a.Quoted && a.Raw == "*") {
+		return true
+	}
+
+	literal, ok := b.Literal()
+	if !ok {
+		return false
+	}
+	if _, ok = a.Literal(); !ok {
+		return matchesPattern(a.Raw, literal)
+	}
+	if field == "ip" {
+		return containsPrefix(a.Raw, literal)
+	}
+	return false
+}
+
+// matchesPattern reports whether a literal value is matched by a pattern, whose unescaped "*" are wildcards
+func matchesPattern(pattern, value string) bool {
+	var parts []string
+	for {
+		before, after, found := cutUnescaped(pattern, '*')
+		parts = append(parts, Unescape(before))
+		if !found {
+			break
+		}
+		pattern = after
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := len(parts) - 1
+	if last == 0 {
+		return value == ""
+	}
+	for _, part := range parts[1:last] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return strings.HasSuffix(value, parts[last])
+}
+
+// containsPrefix reports whether the IP address or CIDR range a contains the IP address or CIDR range b
+func containsPrefix(a, b string) bool {
+	prefixA, okA := parsePrefix(a)
+	prefixB, okB := parsePrefix(b)
+	return okA && okB && prefixA.Bits() <= prefixB.Bits() && prefixA.Contains(prefixB.Addr())
+}
+
+func parsePrefix(value string) (netip.Prefix, bool) {
+	if prefix, err := netip.ParsePrefix(value); err == nil {
+		return prefix.Masked(), true
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), true
+}
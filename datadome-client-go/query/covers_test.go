@@ -0,0 +1,63 @@
+package query
+
+import "testing"
+
+func TestCovers(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"ip:10.0.0.1", "ip: 10.0.0.1", true},
+		{"ip:10.0.0.1", "ip:10.0.0.2", false},
+		{"ip:(10.0.0.1 OR 10.0.0.2)", "ip:10.0.0.2", true},
+		{"ip:10.0.0.2", "ip:(10.0.0.1 OR 10.0.0.2)", false},
+		{"ip:10.0.0.0/8", "ip:(10.1.0.0/16 OR 10.0.0.1)", true},
+		{"ip:10.0.0.0/16", "ip:10.0.0.0/8", false},
+		{"ip:10.0.0.1 OR ip:10.0.0.2", "ip:(10.0.0.1 OR 10.0.0.2)", true},
+		{`headers.user-agent:*curl*`, `headers.user-agent:"curl/8.0"`, true},
+		{`headers.user-agent:curl*`, `headers.user-agent:"libcurl/8.0"`, false},
+		{`headers.user-agent:*bot`, `headers.user-agent:*bot*`, false},
+		{"country:FR", "country:FR AND ip:10.0.0.1", true},
+		{"country:FR AND ip:10.0.0.1", "country:FR", false},
+		{"country:FR AND ip:10.0.0.0/8", "ip:10.0.0.1 AND country:FR", true},
+		{"country:FR OR country:DE", "country:DE AND ip:10.0.0.1", true},
+		{"NOT country:FR", "NOT (country:FR OR country:DE)", true},
+		{"NOT country:FR", "NOT country:DE", false},
+		{"asn:*", "asn:1234", true},
+		{"asn:1234", "ip:10.0.0.1", false},
+	}
+	for _, test := range tests {
+		a, err := Parse(test.a)
+		if err != nil {
+			t.Fatalf("Parse(%q): %s", test.a, err)
+		}
+		b, err := Parse(test.b)
+		if err != nil {
+			t.Fatalf("Parse(%q): %s", test.b, err)
+		}
+		if got := Covers(a, b); got != test.expected {
+			t.Errorf("Covers(%q, %q) = %v, expected %v", test.a, test.b, got, test.expected)
+		}
+	}
+}
+
+func TestMatchesPattern(t *testing.T) {
+	tests := []struct {
+		pattern, value string
+		expected       bool
+	}{
+		{"curl*", "curl/8.0", true},
+		{"*curl", "libcurl", true},
+		{"*url*", "curl/8.0", true},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "acb", false},
+		{`a\*b*`, "a*bc", true},
+		{`a\*b*`, "axbc", false},
+		{"abc", "abc", true},
+	}
+	for _, test := range tests {
+		if got := matchesPattern(test.pattern, test.value); got != test.expected {
+			t.Errorf("matchesPattern(%q, %q) = %v, expected %v", test.pattern, test.value, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Request describes a request evaluated against a query
+type Request struct {
+	// IP is the IP address of the client, matched by the ip field
+	IP string
+	// Domain is the host of the request, matched by the domain field
+	Domain string
+	// URL is the path of the request with its query string, matched by the url field
+	URL string
+	// Method is the HTTP method of the request, matched by the method field
+	Method string
+	// CountryCode is the ISO 3166-1 alpha-2 code of the country of the client, matched by the countrycode and country fields
+	CountryCode string
+	// ASN is the autonomous system number of the client, matched by the asn field with or without the "AS" prefix
+	ASN string
+	// Headers are the headers of the request, matched by the headers.<name> fields. Their names are case-insensitive.
+	Headers map[string]string
+}
+
+// value returns the value of a field of the request, and whether the request has this field
+func (r Request) value(field string) (string, bool, error) {
+	if name, found := strings.CutPrefix(field, "headers."); found && name != "" {
+		for header, value := range r.Headers {
+			if strings.EqualFold(header, name) {
+				return value, true, nil
+			}
+		}
+		return "", false, nil
+	}
+
+	var value string
+	switch field {
+	case "ip":
+		value = r.IP
+	case "domain":
+		value = r.Domain
+	case "url":
+		value = r.URL
+	case "method":
+		value = r.Method
+	case "countrycode", "country":
+		value = r.CountryCode
+	case "asn":
+		value = r.ASN
+	default:
+		return "", false, fmt.Errorf("unsupported field %q", field)
+	}
+	return value, value != "", nil
+}
+
+// Evaluate reports whether the expression matches the request.
+//
+// A term matches when the field of the request matches any of its values: quoted values are compared literally,
+// and the unescaped "*" of the other values are wildcards. The ip field also matches the addresses of CIDR ranges,
+// and the method, country and asn fields are compared case-insensitively. A term never matches a field missing
+// from the request. It raises an error when the expression uses a field which cannot be evaluated.
+func Evaluate(expr Expr, request Request) (bool, error) {
+	switch expr := expr.(type) {
+	case And:
+		for _, operand := range expr.Operands {
+			if matched, err := Evaluate(operand, request); err != nil || !matched {
+				return false, err
+			}
+		}
+		return true, nil
+	case Or:
+		for _, operand := range expr.Operands {
+			if matched, err := Evaluate(operand, request); err != nil || matched {
+				return matched, err
+			}
+		}
+		return false, nil
+	case Not:
+		matched, err := Evaluate(expr.Operand, request)
+		return !matched && err == nil, err
+	case Term:
+		actual, found, err := request.value(expr.Field)
+		if err != nil || !found {
+			return false, err
+		}
+		for _, value := range expr.Values {
+			if matchesValue(expr.Field, value, actual) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+	return false, fmt.Errorf("unsupported expression %T", expr)
+}
+
+// CheckFields returns an error when the expression uses a field which cannot be evaluated by Evaluate,
+// so the expressions can be checked once before evaluating them against several requests.
+func CheckFields(expr Expr) error {
+	switch expr := expr.(type) {
+	case And:
+		for _, operand := range expr.Operands {
+			if err := CheckFields(operand); err != nil {
+				return err
+			}
+		}
+	case Or:
+		for _, operand := range expr.Operands {
+			if err := CheckFields(operand); err != nil {
+				return err
+			}
+		}
+	case Not:
+		return CheckFields(expr.Operand)
+	case Term:
+		_, _, err := Request{}.value(expr.Field)
+		return err
+	}
+	return nil
+}
+
+// matchesValue reports whether the value of a term matches the value of a field of the request
+func matchesValue(field string, value Value, actual string) bool {
+	switch field {
+	case "method", "countrycode", "country":
+		actual = strings.ToUpper(actual)
+		value.Raw = strings.ToUpper(value.Raw)
+	case "asn":
+		actual = trimASPrefix(actual)
+		value.Raw = trimASPrefix(value.Raw)
+	}
+
+	// the IPv6 addresses and ranges are quoted, as they contain ":"
+	if field == "ip" {
+		if literal, ok := value.Literal(); ok && containsPrefix(literal, actual) {
+			return true
+		}
+	}
+	if value.Quoted {
+		return value.Raw == actual
+	}
+	return matchesPattern(value.Raw, actual)
+}
+
+// trimASPrefix removes the "AS" prefix of an autonomous system number
+func trimASPrefix(asn string) string {
+	if len(asn) > 2 && strings.EqualFold(asn[:2], "AS") {
+		return asn[2:]
+	}
+	return asn
+}
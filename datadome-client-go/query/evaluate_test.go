@@ -0,0 +1,102 @@
+package query
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	request := Request{
+		IP:          "10.1.2.3",
+		Domain:      "www.example.com",
+		URL:         "/api/login?next=/home",
+		Method:      "POST",
+		CountryCode: "FR",
+		ASN:         "AS16276",
+		Headers:     map[string]string{"User-Agent": "curl/8.0", "X-Forwarded-Proto": "https"},
+	}
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"ip:10.1.2.3", true},
+		{"ip:10.0.0.0/8", true},
+		{"ip:(192.168.0.0/16 OR 10.1.2.0/24)", true},
+		{"ip:10.1.2.4", false},
+		{"ip:10.1.*", true},
+		{"domain:*.example.com", true},
+		{"domain:example.com", false},
+		{"url:/api/*", true},
+		{`url:"/api/*"`, false},
+		{"method:post", true},
+		{"countrycode:fr AND country:FR", true},
+		{"asn:16276", true},
+		{"asn:AS16276", true},
+		{"headers.user-agent:*curl*", true},
+		{`headers.user-agent:"curl/8.0"`, true},
+		{"headers.referer:*", false},
+		{"NOT headers.referer:*", true},
+		{"ip:10.0.0.0/8 AND NOT countrycode:(FR OR DE)", false},
+		{"countrycode:DE OR headers.x-forwarded-proto:https", true},
+	}
+	for _, test := range tests {
+		expr, err := Parse(test.query)
+		if err != nil {
+			t.Fatalf("Parse(%q) returned an error: %s", test.query, err)
+		}
+		matched, err := Evaluate(expr, request)
+		if err != nil {
+			t.Errorf("Evaluate(%q) returned an error: %s", test.query, err)
+		} else if matched != test.expected {
+			t.Errorf("Evaluate(%q) = %t, expected %t", test.query, matched, test.expected)
+		}
+	}
+}
+
+func TestEvaluate_IPv6(t *testing.T) {
+	tests := []struct {
+		query    Expr
+		expected bool
+	}{
+		{Field("ip").In("2001:db8::/32"), true},
+		{Field("ip").In("2001:db8::1"), true},
+		{Field("ip").In("2001:db9::/32", "10.0.0.0/8"), false},
+	}
+	for _, test := range tests {
+		matched, err := Evaluate(test.query, Request{IP: "2001:db8::1"})
+		if err != nil {
+			t.Errorf("Evaluate(%q) returned an error: %s", test.query, err)
+		} else if matched != test.expected {
+			t.Errorf("Evaluate(%q) = %t, expected %t", test.query, matched, test.expected)
+		}
+	}
+	if query := Field("ip").In("2001:db8::/32").String(); query != `ip:"2001:db8::/32"` {
+		t.Errorf("expected the IPv6 range to be quoted, got %s", query)
+	}
+}
+
+func TestEvaluate_UnsupportedField(t *testing.T) {
+	expr, err := Parse("ip:10.0.0.1 OR tls_fingerprint:abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Evaluate(expr, Request{IP: "10.0.0.2"}); err == nil || err.Error() != `unsupported field "tls_fingerprint"` {
+		t.Errorf("expected an unsupported field error, got %v", err)
+	}
+}
+
+func TestCheckFields(t *testing.T) {
+	expr, err := Parse("ip:10.0.0.1 AND NOT (headers.user-agent:*curl* OR tls_fingerprint:abc)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = CheckFields(expr); err == nil || err.Error() != `unsupported field "tls_fingerprint"` {
+		t.Errorf("expected an unsupported field error, got %v", err)
+	}
+
+	expr, err = Parse("ip:10.0.0.1 AND NOT (headers.user-agent:*curl* OR countrycode:FR)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = CheckFields(expr); err != nil {
+		t.Errorf("expected no error, got %s", err)
+	}
+}
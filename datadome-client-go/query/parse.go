@@ -0,0 +1,237 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenQuoted
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// tokenize splits a query into words, quoted values and parentheses.
+// Backslashes escape the next character of words, and are kept to preserve the wildcards.
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen})
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose})
+		case r == '"':
+			var builder strings.Builder
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				} else if runes[i] == '"' {
+					closed = true
+					break
+				}
+				builder.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated quoted value in query %q", query)
+			}
+			tokens = append(tokens, token{kind: tokenQuoted, value: builder.String()})
+		default:
+			var builder strings.Builder
+			for ; i < len(runes); i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					builder.WriteRune(runes[i])
+					i++
+				} else if unicode.IsSpace(runes[i]) || strings.ContainsRune(`()"`, runes[i]) {
+					i--
+					break
+				}
+				builder.WriteRune(runes[i])
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: builder.String()})
+		}
+	}
+	return tokens, nil
+}
+
+// parser is a recursive descent parser of queries:
+//
+//	or    = and { "OR" and }
+//	and   = unary { "AND" unary }
+//	unary = "NOT" unary | "(" or ")" | term
+//	term  = field ":" ( value | "(" value { "OR" value } ")" )
+type parser struct {
+	query  string
+	tokens []token
+	pos    int
+}
+
+// Parse parses a query made of terms combined with the AND, OR and NOT operators.
+// Nested groups of the same operator are flattened.
+func Parse(query string) (Expr, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("empty query")
+	}
+	p := &parser{query: query, tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, p.errorf("unexpected %s", p.describe(p.tokens[p.pos]))
+	}
+	return expr, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid query %q: %s", p.query, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) describe(t token) string {
+	switch t.kind {
+	case tokenOpen:
+		return `"("`
+	case tokenClose:
+		return `")"`
+	case tokenQuoted:
+		return fmt.Sprintf("value %s", Quote(t.value))
+	}
+	return fmt.Sprintf("%q", t.value)
+}
+
+func (p *parser) peekKeyword(keyword string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenWord && p.tokens[p.pos].value == keyword
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	var operands []Expr
+	for {
+		operand, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if !p.peekKeyword("OR") {
+			break
+		}
+		p.pos++
+	}
+	return AnyOf(operands...), nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	var operands []Expr
+	for {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+		if !p.peekKeyword("AND") {
+			break
+		}
+		p.pos++
+	}
+	return AllOf(operands...), nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("unexpected end of query")
+	}
+	t := p.tokens[p.pos]
+	switch {
+	case t.kind == tokenWord && t.value == "NOT":
+		p.pos++
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Operand: operand}, nil
+	case t.kind == tokenOpen:
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenClose {
+			return nil, p.errorf(`missing ")"`)
+		}
+		p.pos++
+		return expr, nil
+	case t.kind == tokenWord:
+		return p.parseTerm()
+	}
+	return nil, p.errorf("unexpected %s", p.describe(t))
+}
+
+func (p *parser) parseTerm() (Expr, error) {
+	t := p.tokens[p.pos]
+	p.pos++
+
+	field, value, ok := cutUnescaped(t.value, ':')
+	if !ok || field == "" {
+		return nil, p.errorf("expected a field followed by \":\", got %q", t.value)
+	}
+	term := Term{Field: Unescape(field)}
+	if value != "" {
+		term.Values = []Value{{Raw: value}}
+		return term, nil
+	}
+
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("missing value of field %q", term.Field)
+	}
+	next := p.tokens[p.pos]
+	p.pos++
+	switch next.kind {
+	case tokenWord:
+		term.Values = []Value{{Raw: next.value}}
+	case tokenQuoted:
+		term.Values = []Value{{Raw: next.value, Quoted: true}}
+	case tokenOpen:
+		for {
+			if p.pos >= len(p.tokens) {
+				return nil, p.errorf(`missing ")"`)
+			}
+			v := p.tokens[p.pos]
+			p.pos++
+			switch v.kind {
+			case tokenWord:
+				term.Values = append(term.Values, Value{Raw: v.value})
+			case tokenQuoted:
+				term.Values = append(term.Values, Value{Raw: v.value, Quoted: true})
+			default:
+				return nil, p.errorf("unexpected %s in the values of field %q", p.describe(v), term.Field)
+			}
+			if p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenClose {
+				p.pos++
+				break
+			}
+			if !p.peekKeyword("OR") {
+				return nil, p.errorf(`expected "OR" or ")" in the values of field %q`, term.Field)
+			}
+			p.pos++
+		}
+	default:
+		return nil, p.errorf("missing value of field %q", term.Field)
+	}
+	return term, nil
+}
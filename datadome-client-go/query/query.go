@@ -0,0 +1,256 @@
+// Package query builds, parses and formats the queries of DataDome custom rules.
+//
+// A query is made of terms, such as ip:192.168.0.0/24, combined with the AND, OR and NOT operators:
+//
+//	q := query.Field("ip").In("192.168.0.0/24", "10.0.0.1").And(query.Header("user-agent").Contains("curl"))
+//	q.String() // ip:(192.168.0.0/24 OR 10.0.0.1) AND headers.user-agent:*curl*
+//
+// Build formats an expression like String, but raises an error when the expression cannot be parsed back,
+// such as a term built from an empty list of values: query.Field("ip").In() would be formatted "ip:()".
+//
+// Parse converts an existing query into the same types, which can be edited and formatted back with String.
+// Evaluate reports whether a query matches a Request, to test queries without sending traffic.
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Expr is a node of a query
+type Expr interface {
+	// String returns the canonical representation of the expression
+	String() string
+	// And returns an expression matching when this expression and all the operands match
+	And(operands ...Expr) Expr
+	// Or returns an expression matching when this expression or any of the operands matches
+	Or(operands ...Expr) Expr
+	// Not returns an expression matching when this expression does not match
+	Not() Expr
+
+	isExpr()
+}
+
+// And matches when all its operands match
+type And struct {
+	Operands []Expr
+}
+
+// Or matches when any of its operands matches
+type Or struct {
+	Operands []Expr
+}
+
+// Not matches when its operand does not match
+type Not struct {
+	Operand Expr
+}
+
+// Term matches when the field matches any of the values
+type Term struct {
+	Field  string
+	Values []Value
+}
+
+// Value is a value of a term, as written in the query.
+// Quoted values are matched literally, while the unescaped "*" of the other values are wildcards.
+type Value struct {
+	Raw    string
+	Quoted bool
+}
+
+func (And) isExpr()  {}
+func (Or) isExpr()   {}
+func (Not) isExpr()  {}
+func (Term) isExpr() {}
+
+func (e And) And(operands ...Expr) Expr  { return AllOf(append([]Expr{e}, operands...)...) }
+func (e Or) And(operands ...Expr) Expr   { return AllOf(append([]Expr{e}, operands...)...) }
+func (e Not) And(operands ...Expr) Expr  { return AllOf(append([]Expr{e}, operands...)...) }
+func (e Term) And(operands ...Expr) Expr { return AllOf(append([]Expr{e}, operands...)...) }
+
+func (e And) Or(operands ...Expr) Expr  { return AnyOf(append([]Expr{e}, operands...)...) }
+func (e Or) Or(operands ...Expr) Expr   { return AnyOf(append([]Expr{e}, operands...)...) }
+func (e Not) Or(operands ...Expr) Expr  { return AnyOf(append([]Expr{e}, operands...)...) }
+func (e Term) Or(operands ...Expr) Expr { return AnyOf(append([]Expr{e}, operands...)...) }
+
+func (e And) Not() Expr  { return Not{Operand: e} }
+func (e Or) Not() Expr   { return Not{Operand: e} }
+func (e Term) Not() Expr { return Not{Operand: e} }
+
+// Not of a negation returns the negated expression
+func (e Not) Not() Expr { return e.Operand }
+
+// AllOf returns an expression matching when all the operands match.
+// Nested And operands are flattened, and a single operand is returned as is.
+func AllOf(operands ...Expr) Expr {
+	var flattened []Expr
+	for _, operand := range operands {
+		if and, ok := operand.(And); ok {
+			flattened = append(flattened, and.Operands...)
+		} else {
+			flattened = append(flattened, operand)
+		}
+	}
+	if len(flattened) == 1 {
+		return flattened[0]
+	}
+	return And{Operands: flattened}
+}
+
+// AnyOf returns an expression matching when any of the operands matches.
+// Nested Or operands are flattened, and a single operand is returned as is.
+func AnyOf(operands ...Expr) Expr {
+	var flattened []Expr
+	for _, operand := range operands {
+		if or, ok := operand.(Or); ok {
+			flattened = append(flattened, or.Operands...)
+		} else {
+			flattened = append(flattened, operand)
+		}
+	}
+	if len(flattened) == 1 {
+		return flattened[0]
+	}
+	return Or{Operands: flattened}
+}
+
+// specialCharacters must be escaped with a backslash in the values which are not quoted
+const specialCharacters = `()":\*`
+
+// keywords are the operators of the query syntax, which must be quoted to be used as values
+var keywords = []string{"AND", "OR", "NOT"}
+
+// Escape escapes the special characters and the spaces of a value which is not quoted, so it is matched literally
+func Escape(value string) string {
+	var builder strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(specialCharacters, r) || unicode.IsSpace(r) {
+			builder.WriteRune('\\')
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// Unescape removes the backslashes escaping the characters of a value which is not quoted
+func Unescape(value string) string {
+	var builder strings.Builder
+	escaped := false
+	for _, r := range value {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// Quote returns the value between double quotes, escaping the double quotes and backslashes
+func Quote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// Literal returns a value matching the given value exactly, quoted only when needed
+func Literal(value string) Value {
+	if Escape(value) == value && value != "" && !isKeyword(value) {
+		return Value{Raw: value}
+	}
+	return Value{Raw: value, Quoted: true}
+}
+
+// Pattern returns a value in which "*" are wildcards, the other special characters being escaped
+func Pattern(pattern string) Value {
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = Escape(part)
+	}
+	return Value{Raw: strings.Join(parts, "*")}
+}
+
+func isKeyword(value string) bool {
+	for _, keyword := range keywords {
+		if value == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the value as written in a query
+func (v Value) String() string {
+	if v.Quoted {
+		return Quote(v.Raw)
+	}
+	return v.Raw
+}
+
+// Literal returns the value matched by a value without wildcards, and false when the value contains wildcards
+func (v Value) Literal() (string, bool) {
+	if v.Quoted {
+		return v.Raw, true
+	}
+	if _, _, found := cutUnescaped(v.Raw, '*'); found {
+		return "", false
+	}
+	return Unescape(v.Raw), true
+}
+
+func (e And) String() string { return formatOperands(e.Operands, " AND ") }
+func (e Or) String() string  { return formatOperands(e.Operands, " OR ") }
+func (e Not) String() string { return "NOT " + formatOperand(e.Operand) }
+
+func (e Term) String() string {
+	values := make([]string, len(e.Values))
+	for i, v := range e.Values {
+		values[i] = v.String()
+	}
+	if len(values) == 1 {
+		return e.Field + ":" + values[0]
+	}
+	return e.Field + ":(" + strings.Join(values, " OR ") + ")"
+}
+
+func formatOperands(operands []Expr, separator string) string {
+	formatted := make([]string, len(operands))
+	for i, operand := range operands {
+		formatted[i] = formatOperand(operand)
+	}
+	return strings.Join(formatted, separator)
+}
+
+// formatOperand encloses the nested groups in parentheses, so the precedence of the operators does not matter
+func formatOperand(operand Expr) string {
+	switch operand.(type) {
+	case And, Or:
+		return "(" + operand.String() + ")"
+	}
+	return operand.String()
+}
+
+// Canonical returns the canonical representation of a query, or the query itself when it cannot be parsed
+func Canonical(query string) string {
+	expr, err := Parse(query)
+	if err != nil {
+		return query
+	}
+	return expr.String()
+}
+
+// cutUnescaped slices s around the first occurrence of sep which is not escaped by a backslash
+func cutUnescaped(s string, sep rune) (before, after string, found bool) {
+	escaped := false
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
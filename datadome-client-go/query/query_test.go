@@ -0,0 +1,166 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected Expr
+	}{
+		{
+			name:     "term",
+			query:    "ip:192.168.0.1",
+			expected: Term{Field: "ip", Values: []Value{{Raw: "192.168.0.1"}}},
+		},
+		{
+			name:     "space after the field",
+			query:    "ip: 192.168.0.1",
+			expected: Term{Field: "ip", Values: []Value{{Raw: "192.168.0.1"}}},
+		},
+		{
+			name:     "IPv6 value",
+			query:    "ip:2001:db8::1",
+			expected: Term{Field: "ip", Values: []Value{{Raw: "2001:db8::1"}}},
+		},
+		{
+			name:     "quoted value",
+			query:    `user-agent:"say \"hello\" (world)"`,
+			expected: Term{Field: "user-agent", Values: []Value{{Raw: `say "hello" (world)`, Quoted: true}}},
+		},
+		{
+			name:     "several values",
+			query:    `countrycode:(FR OR "DE")`,
+			expected: Term{Field: "countrycode", Values: []Value{{Raw: "FR"}, {Raw: "DE", Quoted: true}}},
+		},
+		{
+			name:  "precedence of AND over OR",
+			query: "url:*api* AND domain:example.org OR NOT ip:1.1.1.1",
+			expected: Or{Operands: []Expr{
+				And{Operands: []Expr{
+					Term{Field: "url", Values: []Value{{Raw: "*api*"}}},
+					Term{Field: "domain", Values: []Value{{Raw: "example.org"}}},
+				}},
+				Not{Operand: Term{Field: "ip", Values: []Value{{Raw: "1.1.1.1"}}}},
+			}},
+		},
+		{
+			name:  "parentheses and flattening",
+			query: `a:1 AND (b:2 AND (c:\(3\) OR d:4))`,
+			expected: And{Operands: []Expr{
+				Term{Field: "a", Values: []Value{{Raw: "1"}}},
+				Term{Field: "b", Values: []Value{{Raw: "2"}}},
+				Or{Operands: []Expr{
+					Term{Field: "c", Values: []Value{{Raw: `\(3\)`}}},
+					Term{Field: "d", Values: []Value{{Raw: "4"}}},
+				}},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := Parse(test.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(expr, test.expected) {
+				t.Errorf("Parse(%q) = %#v, want %#v", test.query, expr, test.expected)
+			}
+		})
+	}
+}
+
+func TestParseQuery_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"":                         "empty query",
+		"ip":                       `expected a field followed by ":"`,
+		"ip:":                      `missing value of field "ip"`,
+		"ip:1.1.1.1 AND":           "unexpected end of query",
+		"(ip:1.1.1.1":              `missing ")"`,
+		"ip:1.1.1.1)":              `unexpected ")"`,
+		"ip:1.1.1.1 url:/":         `unexpected "url:/"`,
+		`ua:"curl`:                 "unterminated quoted value",
+		"ip:(1.1.1.1 AND 2.2.2.2)": `expected "OR" or ")" in the values of field "ip"`,
+	}
+
+	for query, expected := range tests {
+		t.Run(query, func(t *testing.T) {
+			_, err := Parse(query)
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("Parse(%q) error = %v, want it to contain %q", query, err, expected)
+			}
+		})
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	tests := map[string]string{
+		"ip: 192.168.0.1":                      "ip:192.168.0.1",
+		"(a:1 OR b:2)  AND  c:3":               "(a:1 OR b:2) AND c:3",
+		"a:1 OR b:2 AND c:3":                   "a:1 OR (b:2 AND c:3)",
+		`NOT (a:"x y" OR b:(1 OR 2))`:          `NOT (a:"x y" OR b:(1 OR 2))`,
+		"not a valid query (":                  "not a valid query (",
+		`ua:*python\ requests* AND url:/api/*`: `ua:*python\ requests* AND url:/api/*`,
+	}
+
+	for query, expected := range tests {
+		t.Run(query, func(t *testing.T) {
+			if got := Canonical(query); got != expected {
+				t.Errorf("Canonical(%q) = %q, want %q", query, got, expected)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	value := `a (b): "c" \ *d`
+	escaped := Escape(value)
+	if want := `a\ \(b\)\:\ \"c\"\ \\\ \*d`; escaped != want {
+		t.Errorf("Escape = %s, want %s", escaped, want)
+	}
+	if got := Unescape(escaped); got != value {
+		t.Errorf("Unescape = %s, want %s", got, value)
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	tests := map[string]Value{
+		"192.168.0.1":     {Raw: "192.168.0.1"},
+		"python requests": {Raw: "python requests", Quoted: true},
+		"*":               {Raw: "*", Quoted: true},
+		"OR":              {Raw: "OR", Quoted: true},
+		"":                {Raw: "", Quoted: true},
+	}
+
+	for value, expected := range tests {
+		t.Run(value, func(t *testing.T) {
+			got := Literal(value)
+			if got != expected {
+				t.Errorf("Literal(%q) = %#v, want %#v", value, got, expected)
+			}
+			if literal, ok := got.Literal(); !ok || literal != value {
+				t.Errorf("Literal(%q).Literal() = %q, %v", value, literal, ok)
+			}
+		})
+	}
+}
+
+func TestValueLiteral_Wildcard(t *testing.T) {
+	if _, ok := (Value{Raw: "*curl*"}).Literal(); ok {
+		t.Error("expected a value with wildcards not to be literal")
+	}
+	if literal, ok := (Value{Raw: `\*curl`}).Literal(); !ok || literal != "*curl" {
+		t.Errorf("Literal() = %q, %v, want \"*curl\", true", literal, ok)
+	}
+}
+
+func TestPattern(t *testing.T) {
+	if got := Pattern("/api/*/v1 (beta)*").Raw; got != `/api/*/v1\ \(beta\)*` {
+		t.Errorf("Pattern = %s", got)
+	}
+}
@@ -0,0 +1,103 @@
+package datadome
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+)
+
+// parseIPPrefix parses an IP address or a CIDR range into a masked prefix.
+// IPv4-mapped IPv6 addresses are converted to IPv4.
+func parseIPPrefix(value string) (netip.Prefix, error) {
+	if strings.Contains(value, "/") {
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR range %q: %w", value, err)
+		}
+		if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+		return prefix.Masked(), nil
+	}
+
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address %q: %w", value, err)
+	}
+	addr = addr.Unmap().WithZone("")
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// formatIPPrefix returns the address of a single host prefix, and the CIDR notation of the other prefixes
+func formatIPPrefix(prefix netip.Prefix) string {
+	if prefix.IsSingleIP() {
+		return prefix.Addr().String()
+	}
+	return prefix.String()
+}
+
+// compareIPPrefixes orders the prefixes by address, IPv4 first, then by length
+func compareIPPrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
+
+// aggregateIPPrefixes returns the smallest sorted list of prefixes covering the same addresses as the given ones.
+// Prefixes contained in another one are removed, and adjacent prefixes of the same length are merged.
+func aggregateIPPrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	sorted := slices.Clone(prefixes)
+	slices.SortFunc(sorted, compareIPPrefixes)
+
+	var aggregated []netip.Prefix
+	for _, prefix := range sorted {
+		if n := len(aggregated); n > 0 && aggregated[n-1].Overlaps(prefix) {
+			// sorted prefixes overlap only when the previous one contains the next one
+			continue
+		}
+		aggregated = append(aggregated, prefix)
+
+		// merging two halves may complete another pair with the previous prefix
+		for n := len(aggregated); n >= 2; n = len(aggregated) {
+			parent, ok := mergeIPPrefixes(aggregated[n-2], aggregated[n-1])
+			if !ok {
+				break
+			}
+			aggregated = append(aggregated[:n-2], parent)
+		}
+	}
+	return aggregated
+}
+
+// mergeIPPrefixes returns the parent prefix of a and b when they are its two halves
+func mergeIPPrefixes(a, b netip.Prefix) (netip.Prefix, bool) {
+	if a.Bits() != b.Bits() || a.Bits() == 0 || a.Addr().Is4() != b.Addr().Is4() || a == b {
+		return netip.Prefix{}, false
+	}
+	parent := netip.PrefixFrom(a.Addr(), a.Bits()-1).Masked()
+	if parent.Addr() != a.Addr() || !parent.Contains(b.Addr()) {
+		return netip.Prefix{}, false
+	}
+	return parent, true
+}
+
+// aggregateIPs parses and aggregates IP addresses and CIDR ranges, returned in the order of aggregateIPPrefixes
+func aggregateIPs(values []string) ([]string, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		prefix, err := parseIPPrefix(value)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+
+	aggregated := aggregateIPPrefixes(prefixes)
+	result := make([]string, len(aggregated))
+	for i, prefix := range aggregated {
+		result[i] = formatIPPrefix(prefix)
+	}
+	return result, nil
+}
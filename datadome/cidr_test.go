@@ -0,0 +1,67 @@
+package datadome
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateIPs(t *testing.T) {
+	tests := []struct {
+		name     string
+		ips      []string
+		expected []string
+	}{
+		{
+			name:     "single addresses",
+			ips:      []string{"10.0.0.5", "10.0.0.1", "10.0.0.1"},
+			expected: []string{"10.0.0.1", "10.0.0.5"},
+		},
+		{
+			name:     "adjacent addresses",
+			ips:      []string{"10.0.0.1", "10.0.0.0", "10.0.0.2", "10.0.0.3"},
+			expected: []string{"10.0.0.0/30"},
+		},
+		{
+			name:     "contained ranges",
+			ips:      []string{"192.168.1.0/24", "192.168.0.0/16", "192.168.3.4"},
+			expected: []string{"192.168.0.0/16"},
+		},
+		{
+			name:     "cascading merges",
+			ips:      []string{"10.0.0.0/26", "10.0.0.64/26", "10.0.0.128/25", "10.0.1.0/24"},
+			expected: []string{"10.0.0.0/23"},
+		},
+		{
+			name:     "non aligned neighbours",
+			ips:      []string{"10.0.0.1", "10.0.0.2"},
+			expected: []string{"10.0.0.1", "10.0.0.2"},
+		},
+		{
+			name:     "unmasked range",
+			ips:      []string{"172.16.5.7/16"},
+			expected: []string{"172.16.0.0/16"},
+		},
+		{
+			name:     "IPv6",
+			ips:      []string{"2001:db8::1", "2001:db8::/127", "::ffff:10.0.0.1", "10.0.0.0"},
+			expected: []string{"10.0.0.0/31", "2001:db8::/127"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			aggregated, err := aggregateIPs(test.ips)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, aggregated)
+		})
+	}
+}
+
+func TestAggregateIPs_Invalid(t *testing.T) {
+	_, err := aggregateIPs([]string{"10.0.0.1", "10.0.0"})
+	assert.ErrorContains(t, err, `invalid IP address "10.0.0"`)
+
+	_, err = aggregateIPs([]string{"10.0.0.0/33"})
+	assert.ErrorContains(t, err, `invalid CIDR range "10.0.0.0/33"`)
+}
@@ -0,0 +1,132 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// customRulePriorityRanks orders the priorities of the custom rules, the rules of higher rank being applied first
+var customRulePriorityRanks = map[string]int{
+	"high":   3,
+	"normal": 2,
+	"low":    1,
+}
+
+// checkCustomRuleConflicts compares the planned custom rule with the other custom rules of the account listed by list,
+// only when the fields defining which requests it applies to change
+func checkCustomRuleConflicts(ctx context.Context, data *schema.ResourceDiff, meta interface{}, list func() ([]dd.CustomRule, error)) error {
+	if data.Id() != "" && !data.HasChanges("query", "endpoint_type", "priority", "response", "enabled") {
+		return nil
+	}
+	if !data.NewValueKnown("query") || !data.NewValueKnown("endpoint_type") {
+		return nil
+	}
+	// enabled is unknown when it is not set on creation, the custom rule being enabled by default
+	if data.NewValueKnown("enabled") && !data.Get("enabled").(bool) {
+		return nil
+	}
+
+	customRule := dd.CustomRule{
+		Name:         data.Get("name").(string),
+		Query:        data.Get("query").(string),
+		EndpointType: data.Get("endpoint_type").(string),
+		Priority:     data.Get("priority").(string),
+		Response:     data.Get("response").(string),
+	}
+	if policyOptions, err := expandPolicyOptions(data.Get("policy_options").([]interface{})); err == nil {
+		customRule.PolicyOptions = policyOptions
+	}
+	if id, err := strconv.Atoi(priorResourceID(data)); err == nil {
+		customRule.ID = &id
+	}
+
+	customRules, err := list()
+	if err != nil {
+		log.Printf("[WARN] unable to list custom rules to check the conflicts of custom rule %q: %s", customRule.Name, err)
+		return nil
+	}
+
+	return reportPlanFindings(ctx, meta, findCustomRuleConflicts(customRule, customRules))
+}
+
+// findCustomRuleConflicts returns the conflicts between a custom rule and the other custom rules:
+// - a rule on the same endpoint type and priority, whose query is the same, covers or is covered by the query of the rule,
+// with a different response
+// - a rule of higher priority covering the query of the rule on its endpoint type, so the rule never applies
+// - a rule of lower priority whose query is covered by the rule on its endpoint type, so it never applies
+// The expired rules and the rules not active yet are ignored. A rule limited by a time box or a rate limit
+// does not respond to all the requests it matches, so it is not reported as shadowing another rule.
+func findCustomRuleConflicts(customRule dd.CustomRule, customRules []dd.CustomRule) []planFinding {
+	expr, err := query.Parse(customRule.Query)
+	if err != nil {
+		return nil
+	}
+	rank := customRulePriorityRanks[customRule.Priority]
+
+	var findings []planFinding
+	for _, other := range customRules {
+		if customRule.ID != nil && other.ID != nil && *other.ID == *customRule.ID {
+			continue
+		}
+		if other.Enabled != nil && !*other.Enabled {
+			continue
+		}
+		if isCustomRuleExpired(other.ExpiredAt) || isCustomRuleInactive(other.ActivatedAt) {
+			continue
+		}
+		otherExpr, err := query.Parse(other.Query)
+		if err != nil {
+			continue
+		}
+		covers, covered := query.Covers(expr, otherExpr), query.Covers(otherExpr, expr)
+		otherRank := customRulePriorityRanks[other.Priority]
+
+		switch {
+		case other.EndpointType == customRule.EndpointType && otherRank == rank && other.Response != customRule.Response && (covers || covered):
+			relation := "the same query as"
+			switch {
+			case covers && !covered:
+				relation = "a query covered by"
+			case covered && !covers:
+				relation = "a query covering"
+			}
+			findings = append(findings, planFinding{
+				Summary: "Conflicting custom rules",
+				Detail: fmt.Sprintf("The custom rule %q has %s the query of custom rule %q on the endpoint type %q with the priority %q, but responds %q instead of %q.",
+					other.Name, relation, customRule.Name, describeEndpointType(customRule.EndpointType), customRule.Priority, other.Response, customRule.Response),
+			})
+		case otherRank > rank && covered && !isCustomRuleLimited(other) && (other.EndpointType == "" || other.EndpointType == customRule.EndpointType):
+			findings = append(findings, planFinding{
+				Summary: "Shadowed custom rule",
+				Detail: fmt.Sprintf("The custom rule %q with the priority %q matches all the requests of custom rule %q with the priority %q on the endpoint type %q, so custom rule %q never applies.",
+					other.Name, other.Priority, customRule.Name, customRule.Priority, describeEndpointType(customRule.EndpointType), customRule.Name),
+			})
+		case rank > otherRank && covers && !isCustomRuleLimited(customRule) && (customRule.EndpointType == "" || other.EndpointType == customRule.EndpointType):
+			findings = append(findings, planFinding{
+				Summary: "Shadowed custom rule",
+				Detail: fmt.Sprintf("The custom rule %q with the priority %q matches all the requests of custom rule %q with the priority %q on the endpoint type %q, so custom rule %q never applies.",
+					customRule.Name, customRule.Priority, other.Name, other.Priority, describeEndpointType(other.EndpointType), other.Name),
+			})
+		}
+	}
+	return findings
+}
+
+// isCustomRuleLimited reports whether the custom rule only applies its response within a time box or up to a rate limit
+func isCustomRuleLimited(customRule dd.CustomRule) bool {
+	return customRule.PolicyOptions != nil && (customRule.PolicyOptions.TimeBox != nil || customRule.PolicyOptions.RateLimit != nil)
+}
+
+// describeEndpointType returns the endpoint type of a custom rule, or "all" when the rule applies to all endpoint types
+func describeEndpointType(endpointType string) string {
+	if endpointType == "" {
+		return "all"
+	}
+	return endpointType
+}
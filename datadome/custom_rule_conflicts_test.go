@@ -0,0 +1,156 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindCustomRuleConflicts(t *testing.T) {
+	id, otherID := 1, 2
+	disabled := false
+	past, future := "2020-01-01 00:00:00", "2099-01-01 00:00:00"
+	timeBox := &dd.PolicyOptions{TimeBox: &dd.TimeBoxOptions{AuthorizedHoursOfTheWeek: []int{9, 10}, ResponseOutsideTimeBox: "allow"}}
+	rateLimit := &dd.PolicyOptions{RateLimit: &dd.RateLimitOptions{AppliesTo: "ip", Threshold: 100, TimeFrame: "1m", ResponseAfterThreshold: "block"}}
+	customRule := dd.CustomRule{ID: &id, Name: "allow-partner", Query: "ip:10.0.0.1", Response: "allow", Priority: "normal", EndpointType: "web"}
+
+	tests := []struct {
+		name     string
+		other    dd.CustomRule
+		expected []planFinding
+	}{
+		{
+			name:  "same query with another response",
+			other: dd.CustomRule{ID: &otherID, Name: "block", Query: "ip: 10.0.0.1", Response: "block", Priority: "normal", EndpointType: "web"},
+			expected: []planFinding{{
+				Summary: "Conflicting custom rules",
+				Detail:  `The custom rule "block" has the same query as the query of custom rule "allow-partner" on the endpoint type "web" with the priority "normal", but responds "block" instead of "allow".`,
+			}},
+		},
+		{
+			name:  "covering query with another response",
+			other: dd.CustomRule{ID: &otherID, Name: "block", Query: "ip:10.0.0.0/8", Response: "block", Priority: "normal", EndpointType: "web"},
+			expected: []planFinding{{
+				Summary: "Conflicting custom rules",
+				Detail:  `The custom rule "block" has a query covering the query of custom rule "allow-partner" on the endpoint type "web" with the priority "normal", but responds "block" instead of "allow".`,
+			}},
+		},
+		{
+			name:  "same query and response",
+			other: dd.CustomRule{ID: &otherID, Name: "allow", Query: "ip:10.0.0.1", Response: "allow", Priority: "normal", EndpointType: "web"},
+		},
+		{
+			name:  "other endpoint type",
+			other: dd.CustomRule{ID: &otherID, Name: "block", Query: "ip:10.0.0.1", Response: "block", Priority: "normal", EndpointType: "login"},
+		},
+		{
+			name:  "disabled rule",
+			other: dd.CustomRule{ID: &otherID, Name: "block", Query: "ip:10.0.0.1", Response: "block", Priority: "normal", EndpointType: "web", Enabled: &disabled},
+		},
+		{
+			name:  "the rule itself",
+			other: dd.CustomRule{ID: &id, Name: "allow-partner", Query: "ip:10.0.0.1", Response: "block", Priority: "normal", EndpointType: "web"},
+		},
+		{
+			name:  "shadowed by a rule of higher priority on all endpoint types",
+			other: dd.CustomRule{ID: &otherID, Name: "block-all", Query: "ip:10.0.0.0/8", Response: "block", Priority: "high"},
+			expected: []planFinding{{
+				Summary: "Shadowed custom rule",
+				Detail:  `The custom rule "block-all" with the priority "high" matches all the requests of custom rule "allow-partner" with the priority "normal" on the endpoint type "web", so custom rule "allow-partner" never applies.`,
+			}},
+		},
+		{
+			name:  "shadowing a rule of lower priority",
+			other: dd.CustomRule{ID: &otherID, Name: "captcha", Query: "ip:10.0.0.1 AND country:FR", Response: "captcha", Priority: "low", EndpointType: "web"},
+			expected: []planFinding{{
+				Summary: "Shadowed custom rule",
+				Detail:  `The custom rule "allow-partner" with the priority "normal" matches all the requests of custom rule "captcha" with the priority "low" on the endpoint type "web", so custom rule "captcha" never applies.`,
+			}},
+		},
+		{
+			name:  "expired rule of higher priority",
+			other: dd.CustomRule{ID: &otherID, Name: "block-all", Query: "ip:10.0.0.0/8", Response: "block", Priority: "high", ExpiredAt: &past},
+		},
+		{
+			name:  "rule of higher priority not active yet",
+			other: dd.CustomRule{ID: &otherID, Name: "block-all", Query: "ip:10.0.0.0/8", Response: "block", Priority: "high", ActivatedAt: &future},
+		},
+		{
+			name:  "expired rule with the same query and another response",
+			other: dd.CustomRule{ID: &otherID, Name: "block", Query: "ip:10.0.0.1", Response: "block", Priority: "normal", EndpointType: "web", ExpiredAt: &past},
+		},
+		{
+			name:  "time boxed rule of higher priority",
+			other: dd.CustomRule{ID: &otherID, Name: "block-all", Query: "ip:10.0.0.0/8", Response: "block", Priority: "high", PolicyOptions: timeBox},
+		},
+		{
+			name:  "rate limited rule of higher priority",
+			other: dd.CustomRule{ID: &otherID, Name: "block-all", Query: "ip:10.0.0.0/8", Response: "allow", Priority: "high", PolicyOptions: rateLimit},
+		},
+		{
+			name:  "partially overlapping rule of higher priority",
+			other: dd.CustomRule{ID: &otherID, Name: "block", Query: "ip:10.0.0.1 AND country:FR", Response: "block", Priority: "high", EndpointType: "web"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, findCustomRuleConflicts(customRule, []dd.CustomRule{test.other}))
+		})
+	}
+}
+
+func TestFindCustomRuleConflicts_limitedRule(t *testing.T) {
+	id, otherID := 1, 2
+	customRule := dd.CustomRule{
+		ID:           &id,
+		Name:         "allow-partner",
+		Query:        "ip:10.0.0.0/8",
+		Response:     "allow",
+		Priority:     "normal",
+		EndpointType: "web",
+		PolicyOptions: &dd.PolicyOptions{
+			TimeBox: &dd.TimeBoxOptions{AuthorizedHoursOfTheWeek: []int{9, 10}, ResponseOutsideTimeBox: "block"},
+		},
+	}
+	other := dd.CustomRule{ID: &otherID, Name: "captcha", Query: "ip:10.0.0.1", Response: "captcha", Priority: "low", EndpointType: "web"}
+
+	assert.Empty(t, findCustomRuleConflicts(customRule, []dd.CustomRule{other}))
+}
+
+func TestPlanWarnings_conflictingCustomRule(t *testing.T) {
+	mockClient := dd.NewMockClientCustomRule()
+	_, err := mockClient.Create(context.Background(), dd.CustomRule{
+		Name:         "acc-test-block",
+		Query:        "ip: 192.168.0.1",
+		Response:     "block",
+		Priority:     "low",
+		EndpointType: "web",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	customRule := map[string]interface{}{
+		"name":          "acc-test",
+		"query":         "ip:192.168.0.1",
+		"response":      "allow",
+		"endpoint_type": "web",
+		"priority":      "low",
+	}
+
+	resp := testPlanCustomRule(t, &ProviderConfig{ClientCustomRule: mockClient}, customRule)
+
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, resp.Diagnostics[0].Severity)
+		assert.Equal(t, "Conflicting custom rules", resp.Diagnostics[0].Summary)
+	}
+
+	resp = testPlanCustomRule(t, &ProviderConfig{ClientCustomRule: mockClient, StrictPlanChecks: true}, customRule)
+
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityError, resp.Diagnostics[0].Severity)
+		assert.Contains(t, resp.Diagnostics[0].Summary, `Conflicting custom rules: The custom rule "acc-test-block" has the same query`)
+	}
+}
@@ -0,0 +1,142 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/datadome/terraform-provider/common"
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// endpointTypesBySource maps the source and the traffic usage of an endpoint to the endpoint type of the custom rules applied to it
+var endpointTypesBySource = map[string]map[string]string{
+	"Web Browser": {
+		"General":          "web",
+		"Account Creation": "account-creation",
+		"Login":            "login",
+		"Cart":             "cart",
+		"Form":             "submit",
+		"Forms":            "forms",
+		"Payment":          "payment-web",
+		"Rss":              "rss",
+	},
+	"Mobile App": {
+		"General":          "api-app-mobile",
+		"Account Creation": "account-creation-app-mobile",
+		"Login":            "api-app-mobile-login",
+		"Cart":             "cart-app-mobile",
+		"Forms":            "forms-app-mobile",
+		"Payment":          "payment-app-mobile",
+	},
+	"Agentic Protocol": {
+		"General":          "agentic-general",
+		"Account Creation": "agentic-account-creation",
+		"Login":            "agentic-login",
+		"Cart":             "agentic-cart",
+		"Forms":            "agentic-forms",
+		"Payment":          "agentic-payment",
+	},
+	"Api": {
+		"General": "api",
+	},
+}
+
+// endpointTypeOf returns the endpoint type of the custom rules applied to an endpoint
+func endpointTypeOf(endpoint dd.Endpoint) (string, error) {
+	endpointType, ok := endpointTypesBySource[endpoint.Source][endpoint.TrafficUsage]
+	if !ok {
+		return "", fmt.Errorf("no custom rule endpoint type matches the source %q and the traffic usage %q of endpoint %q", endpoint.Source, endpoint.TrafficUsage, endpoint.Name)
+	}
+	return endpointType, nil
+}
+
+// readEndpointType reads the endpoint with the given ID and returns the endpoint type of the custom rules applied to it
+func readEndpointType(ctx context.Context, c common.API[dd.Endpoint, string], endpointID string) (string, error) {
+	endpoint, err := c.Read(ctx, endpointID)
+	if err != nil {
+		return "", err
+	}
+	if endpoint == nil {
+		return "", fmt.Errorf("endpoint %q not found", endpointID)
+	}
+	return endpointTypeOf(*endpoint)
+}
+
+// resolveCustomRuleEndpointType returns the endpoint type of a custom rule to create or update,
+// read from the referenced endpoint when endpoint_id is set. The endpoint may have changed since the plan,
+// so only an endpoint_type set in the configuration is checked against it.
+func resolveCustomRuleEndpointType(ctx context.Context, data *schema.ResourceData, meta interface{}) (string, error) {
+	// an endpoint_type removed from the configuration is planned as unknown, as it is computed
+	configured := ""
+	if rawConfig := data.GetRawConfig(); !rawConfig.IsNull() {
+		if configuredType := rawConfig.GetAttr("endpoint_type"); configuredType.IsKnown() && !configuredType.IsNull() {
+			configured = configuredType.AsString()
+		}
+	} else {
+		configured = data.Get("endpoint_type").(string)
+	}
+
+	endpointID := data.Get("endpoint_id").(string)
+	if endpointID == "" {
+		return configured, nil
+	}
+
+	endpointType, err := readEndpointType(ctx, meta.(*ProviderConfig).ClientEndpoint, endpointID)
+	if err != nil {
+		return "", err
+	}
+	if configured != "" && configured != endpointType {
+		return "", fmt.Errorf("endpoint_type %q contradicts the endpoint %q, whose source and traffic usage require %q", configured, endpointID, endpointType)
+	}
+	return endpointType, nil
+}
+
+// customizeDiffCustomRuleEndpointType derives the endpoint_type of a custom rule from the endpoint referenced by endpoint_id,
+// and raises an error when the configured endpoint_type contradicts it.
+// The derived endpoint_type is unknown until the apply when endpoint_id is unknown or changes,
+// as the referenced endpoint may be created or updated in the same apply.
+// Without endpoint_id, the endpoint_type removed from the configuration is planned to be cleared.
+func customizeDiffCustomRuleEndpointType(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
+	rawConfig := data.GetRawConfig()
+	if rawConfig.IsNull() {
+		return nil
+	}
+	rawEndpointID := rawConfig.GetAttr("endpoint_id")
+	configuredType := rawConfig.GetAttr("endpoint_type")
+
+	if rawEndpointID.IsNull() {
+		if configuredType.IsNull() && data.Get("endpoint_type").(string) != "" {
+			return data.SetNew("endpoint_type", "")
+		}
+		return nil
+	}
+	if !rawEndpointID.IsKnown() {
+		// the endpoint is not created yet, its endpoint type is read on apply
+		if configuredType.IsNull() {
+			return data.SetNewComputed("endpoint_type")
+		}
+		return nil
+	}
+
+	if configuredType.IsNull() && data.HasChange("endpoint_id") {
+		return data.SetNewComputed("endpoint_type")
+	}
+
+	endpointID := rawEndpointID.AsString()
+	endpointType, err := readEndpointType(ctx, meta.(*ProviderConfig).ClientEndpoint, endpointID)
+	if err != nil {
+		return fmt.Errorf("endpoint_id: %w", err)
+	}
+
+	if configuredType.IsKnown() && !configuredType.IsNull() {
+		if configuredType.AsString() != endpointType {
+			return fmt.Errorf("endpoint_type %q contradicts the endpoint %q, whose source and traffic usage require %q", configuredType.AsString(), endpointID, endpointType)
+		}
+		return nil
+	}
+	if data.Get("endpoint_type").(string) != endpointType {
+		return data.SetNew("endpoint_type", endpointType)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package datadome
+
+import (
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointTypeOf(t *testing.T) {
+	tests := []struct {
+		source       string
+		trafficUsage string
+		expected     string
+	}{
+		{"Web Browser", "General", "web"},
+		{"Web Browser", "Payment", "payment-web"},
+		{"Mobile App", "Login", "api-app-mobile-login"},
+		{"Mobile App", "General", "api-app-mobile"},
+		{"Agentic Protocol", "Cart", "agentic-cart"},
+		{"Api", "General", "api"},
+	}
+	for _, test := range tests {
+		endpointType, err := endpointTypeOf(dd.Endpoint{Source: test.source, TrafficUsage: test.trafficUsage})
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, endpointType, "%s + %s", test.source, test.trafficUsage)
+	}
+
+	// every derived endpoint type is accepted by the custom rules
+	for _, trafficUsages := range endpointTypesBySource {
+		for _, endpointType := range trafficUsages {
+			assert.Contains(t, customRuleEndpointTypes, endpointType)
+		}
+	}
+
+	_, err := endpointTypeOf(dd.Endpoint{Name: "api", Source: "Api", TrafficUsage: "Login"})
+	assert.EqualError(t, err, `no custom rule endpoint type matches the source "Api" and the traffic usage "Login" of endpoint "api"`)
+}
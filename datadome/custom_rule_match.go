@@ -0,0 +1,266 @@
+package datadome
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// customRuleMatchMaxDepth is the number of any and all groups which can be nested in a match block
+const customRuleMatchMaxDepth = 3
+
+// customRuleMatchOperators lists the operators of a match condition
+var customRuleMatchOperators = []string{"equals", "not_equals", "contains", "not_contains", "starts_with", "ends_with"}
+
+// queryFieldRegexp matches the names of the fields of a query, such as "ip" or "headers.user-agent"
+var queryFieldRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// customRuleMatchSchema returns the schema of a match block, in which any and all groups can be nested up to depth times
+func customRuleMatchSchema(depth int) *schema.Resource {
+	match := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"field": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(queryFieldRegexp, "expected a field name such as \"ip\" or \"countrycode\""),
+			},
+			"operator": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(customRuleMatchOperators, false),
+			},
+			"values": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+		},
+	}
+
+	if depth > 0 {
+		for _, group := range []string{"any", "all"} {
+			match.Schema[group] = &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     customRuleMatchSchema(depth - 1),
+			}
+		}
+	}
+
+	return match
+}
+
+// compileCustomRuleMatches converts the match blocks of a custom rule into a query, matching when all the blocks match
+func compileCustomRuleMatches(matches []interface{}) (string, error) {
+	expr, err := expandCustomRuleMatches(matches, "all", "match")
+	if err != nil {
+		return "", err
+	}
+	return expr.String(), nil
+}
+
+// expandCustomRuleMatches converts match blocks into the operands of an any or all group
+func expandCustomRuleMatches(matches []interface{}, group, path string) (query.Expr, error) {
+	operands := make([]query.Expr, 0, len(matches))
+	for i, v := range matches {
+		match, _ := v.(map[string]interface{})
+		operand, err := expandCustomRuleMatch(match, fmt.Sprintf("%s.%d", path, i))
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+
+	switch {
+	case len(operands) == 0:
+		return nil, fmt.Errorf("%s: at least one match block is required", path)
+	case group == "any":
+		return query.AnyOf(operands...), nil
+	}
+	return query.AllOf(operands...), nil
+}
+
+// expandCustomRuleMatch converts a match block, which is either a condition or a group, into a query expression
+func expandCustomRuleMatch(match map[string]interface{}, path string) (query.Expr, error) {
+	field, _ := match["field"].(string)
+	operator, _ := match["operator"].(string)
+	values, _ := match["values"].([]interface{})
+	anyMatches, _ := match["any"].([]interface{})
+	allMatches, _ := match["all"].([]interface{})
+
+	isCondition := field != "" || operator != "" || len(values) > 0
+	switch {
+	case isCondition && (len(anyMatches) > 0 || len(allMatches) > 0):
+		return nil, fmt.Errorf("%s: a match block must define either a condition with field, operator and values, or one of the any and all groups", path)
+	case len(anyMatches) > 0 && len(allMatches) > 0:
+		return nil, fmt.Errorf("%s: only one of the any and all groups can be defined in a match block", path)
+	case len(anyMatches) > 0:
+		return expandCustomRuleMatches(anyMatches, "any", path+".any")
+	case len(allMatches) > 0:
+		return expandCustomRuleMatches(allMatches, "all", path+".all")
+	case field == "" || operator == "" || len(values) == 0:
+		return nil, fmt.Errorf("%s: field, operator and values are required by a match condition", path)
+	}
+
+	term := query.Term{Field: field}
+	for _, v := range values {
+		value, _ := v.(string)
+		if value == "" {
+			return nil, fmt.Errorf("%s: values must not be empty", path)
+		}
+		term.Values = append(term.Values, expandCustomRuleMatchValue(operator, value))
+	}
+
+	if strings.HasPrefix(operator, "not_") {
+		return term.Not(), nil
+	}
+	return term, nil
+}
+
+// expandCustomRuleMatchValue converts the value of a condition into a query value, using wildcards for the partial matches
+func expandCustomRuleMatchValue(operator, value string) query.Value {
+	switch operator {
+	case "contains", "not_contains":
+		return query.Value{Raw: "*" + query.Escape(value) + "*"}
+	case "starts_with":
+		return query.Value{Raw: query.Escape(value) + "*"}
+	case "ends_with":
+		return query.Value{Raw: "*" + query.Escape(value)}
+	}
+	return query.Literal(value)
+}
+
+// flattenCustomRuleMatches converts a query into match blocks, the reverse of compileCustomRuleMatches.
+// It returns false when the query cannot be represented by match blocks.
+func flattenCustomRuleMatches(q string) ([]interface{}, bool) {
+	expr, err := query.Parse(q)
+	if err != nil {
+		return nil, false
+	}
+
+	operands := []query.Expr{expr}
+	if and, ok := expr.(query.And); ok {
+		operands = and.Operands
+	}
+
+	matches := make([]interface{}, 0, len(operands))
+	for _, operand := range operands {
+		match, ok := flattenCustomRuleMatch(operand, customRuleMatchMaxDepth)
+		if !ok {
+			return nil, false
+		}
+		matches = append(matches, match)
+	}
+	return matches, true
+}
+
+// flattenCustomRuleMatch converts a query expression into a match block, with up to depth nested groups
+func flattenCustomRuleMatch(expr query.Expr, depth int) (map[string]interface{}, bool) {
+	switch e := expr.(type) {
+	case query.And, query.Or:
+		if depth == 0 {
+			return nil, false
+		}
+		group, operands := "all", []query.Expr(nil)
+		if or, ok := e.(query.Or); ok {
+			group, operands = "any", or.Operands
+		} else {
+			operands = e.(query.And).Operands
+		}
+		matches := make([]interface{}, 0, len(operands))
+		for _, operand := range operands {
+			match, ok := flattenCustomRuleMatch(operand, depth-1)
+			if !ok {
+				return nil, false
+			}
+			matches = append(matches, match)
+		}
+		return map[string]interface{}{group: matches}, true
+	case query.Not:
+		term, ok := e.Operand.(query.Term)
+		if !ok {
+			return nil, false
+		}
+		match, ok := flattenCustomRuleCondition(term)
+		if !ok {
+			return nil, false
+		}
+		switch match["operator"] {
+		case "equals", "contains":
+			match["operator"] = "not_" + match["operator"].(string)
+			return match, true
+		}
+		return nil, false
+	case query.Term:
+		return flattenCustomRuleCondition(e)
+	}
+	return nil, false
+}
+
+// flattenCustomRuleCondition converts a query term into a match condition, when all its values use the same operator
+func flattenCustomRuleCondition(term query.Term) (map[string]interface{}, bool) {
+	operator := ""
+	values := make([]interface{}, 0, len(term.Values))
+	for _, v := range term.Values {
+		valueOperator, value, ok := flattenCustomRuleMatchValue(v)
+		if !ok || (operator != "" && operator != valueOperator) {
+			return nil, false
+		}
+		operator = valueOperator
+		values = append(values, value)
+	}
+	return map[string]interface{}{
+		"field":    term.Field,
+		"operator": operator,
+		"values":   values,
+	}, true
+}
+
+// flattenCustomRuleMatchValue returns the operator and the value of a condition matching the query value
+func flattenCustomRuleMatchValue(v query.Value) (string, string, bool) {
+	if value, ok := v.Literal(); ok {
+		return "equals", value, true
+	}
+
+	raw := v.Raw
+	leading := strings.HasPrefix(raw, "*")
+	if leading {
+		raw = raw[1:]
+	}
+	trailing := strings.HasSuffix(raw, "*") && !isEscapedQueryCharacter(raw, len(raw)-1)
+	if trailing {
+		raw = raw[:len(raw)-1]
+	}
+	// other wildcards cannot be represented by a condition
+	value, ok := query.Value{Raw: raw}.Literal()
+	if raw == "" || !ok {
+		return "", "", false
+	}
+
+	switch {
+	case leading && trailing:
+		return "contains", value, true
+	case leading:
+		return "ends_with", value, true
+	case trailing:
+		return "starts_with", value, true
+	}
+	return "equals", value, true
+}
+
+// isEscapedQueryCharacter reports whether the character at index i of a value is escaped by an odd number of backslashes
+func isEscapedQueryCharacter(value string, i int) bool {
+	backslashes := 0
+	for j := i - 1; j >= 0 && value[j] == '\\'; j-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
@@ -0,0 +1,145 @@
+package datadome
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileCustomRuleMatches(t *testing.T) {
+	matches := []interface{}{
+		map[string]interface{}{"field": "ip", "operator": "equals", "values": []interface{}{"192.168.0.0/24", "10.0.0.1"}},
+		map[string]interface{}{"any": []interface{}{
+			map[string]interface{}{"field": "user-agent", "operator": "contains", "values": []interface{}{"curl", "python requests"}},
+			map[string]interface{}{"field": "url", "operator": "starts_with", "values": []interface{}{"/api/"}},
+			map[string]interface{}{"all": []interface{}{
+				map[string]interface{}{"field": "countrycode", "operator": "not_equals", "values": []interface{}{"FR"}},
+				map[string]interface{}{"field": "referer", "operator": "equals", "values": []interface{}{"OR", `say "hi"`}},
+			}},
+		}},
+	}
+
+	query, err := compileCustomRuleMatches(matches)
+	assert.NoError(t, err)
+	assert.Equal(t, `ip:(192.168.0.0/24 OR 10.0.0.1) AND (user-agent:(*curl* OR *python\ requests*) OR url:/api/* OR (NOT countrycode:FR AND referer:("OR" OR "say \"hi\"")))`, query)
+
+	flattened, ok := flattenCustomRuleMatches(query)
+	assert.True(t, ok)
+	assert.Equal(t, matches, flattened)
+}
+
+func TestCompileCustomRuleMatches_SingleCondition(t *testing.T) {
+	query, err := compileCustomRuleMatches([]interface{}{
+		map[string]interface{}{"field": "url", "operator": "not_contains", "values": []interface{}{"admin"}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT url:*admin*", query)
+}
+
+func TestCompileCustomRuleMatches_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		matches  []interface{}
+		expected string
+	}{
+		{
+			name:     "incomplete condition",
+			matches:  []interface{}{map[string]interface{}{"field": "ip", "values": []interface{}{"1.1.1.1"}}},
+			expected: "match.0: field, operator and values are required by a match condition",
+		},
+		{
+			name: "condition and group",
+			matches: []interface{}{map[string]interface{}{
+				"field":    "ip",
+				"operator": "equals",
+				"values":   []interface{}{"1.1.1.1"},
+				"any":      []interface{}{map[string]interface{}{"field": "ip", "operator": "equals", "values": []interface{}{"2.2.2.2"}}},
+			}},
+			expected: "match.0: a match block must define either a condition",
+		},
+		{
+			name: "any and all groups",
+			matches: []interface{}{map[string]interface{}{
+				"any": []interface{}{map[string]interface{}{"field": "ip", "operator": "equals", "values": []interface{}{"1.1.1.1"}}},
+				"all": []interface{}{map[string]interface{}{"field": "ip", "operator": "equals", "values": []interface{}{"2.2.2.2"}}},
+			}},
+			expected: "match.0: only one of the any and all groups",
+		},
+		{
+			name: "nested incomplete condition",
+			matches: []interface{}{map[string]interface{}{
+				"any": []interface{}{map[string]interface{}{}},
+			}},
+			expected: "match.0.any.0: field, operator and values are required",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := compileCustomRuleMatches(test.matches)
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestFlattenCustomRuleMatches(t *testing.T) {
+	flattened, ok := flattenCustomRuleMatches("ip: 192.168.0.1 AND url:*\\*admin")
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"field": "ip", "operator": "equals", "values": []interface{}{"192.168.0.1"}},
+		map[string]interface{}{"field": "url", "operator": "ends_with", "values": []interface{}{"*admin"}},
+	}, flattened)
+}
+
+func TestFlattenCustomRuleMatches_NotRepresentable(t *testing.T) {
+	for _, query := range []string{
+		"url:/api/*/admin",
+		"url:(*api* OR /admin)",
+		"NOT (ip:1.1.1.1 OR ip:2.2.2.2)",
+		"NOT url:/api/*",
+		"a:1 AND (b:1 OR (c:1 AND (d:1 OR (e:1 AND f:1))))",
+		"invalid (",
+	} {
+		t.Run(query, func(t *testing.T) {
+			_, ok := flattenCustomRuleMatches(query)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestResourceCustomRuleRead_QueryNotRepresentable(t *testing.T) {
+	mockClient := dd.NewMockClientCustomRule()
+	id, err := mockClient.Create(context.Background(), dd.CustomRule{
+		Name:         "acc-test",
+		Query:        "url:/api/*/admin",
+		Response:     "block",
+		EndpointType: "web",
+		Priority:     "low",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches := []interface{}{
+		map[string]interface{}{"field": "ip", "operator": "equals", "values": []interface{}{"192.168.0.1"}},
+	}
+	data := schema.TestResourceDataRaw(t, resourceCustomRule().Schema, map[string]interface{}{
+		"name":     "acc-test",
+		"match":    matches,
+		"response": "block",
+	})
+	data.SetId(strconv.Itoa(*id))
+
+	diags := resourceCustomRuleRead(context.Background(), data, &ProviderConfig{ClientCustomRule: mockClient})
+
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, diag.Warning, diags[0].Severity)
+		assert.Equal(t, "Query not representable by match blocks", diags[0].Summary)
+	}
+	assert.Equal(t, "url:/api/*/admin", data.Get("query"))
+	assert.Equal(t, "192.168.0.1", data.Get("match.0.values.0"))
+}
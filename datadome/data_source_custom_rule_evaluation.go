@@ -0,0 +1,262 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceCustomRuleEvaluation define the read operation and the schema definition to find the custom rules matching sample requests.
+func dataSourceCustomRuleEvaluation() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceCustomRuleEvaluationRead,
+		Schema: map[string]*schema.Schema{
+			"request": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"endpoint_type": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(customRuleEndpointTypes, false),
+						},
+						"ip": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.IsIPAddress,
+						},
+						"domain": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"url": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "/",
+						},
+						"method": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "GET",
+						},
+						"country_code": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"asn": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"headers": {
+							Type:     schema.TypeMap,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+			"custom_rule": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+						"query": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"response": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(customRuleResponses, false),
+						},
+						"priority": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice(customRulePriorities, false),
+							Default:      "high",
+						},
+						"endpoint_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice(customRuleEndpointTypes, false),
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+					},
+				},
+			},
+			"result": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"matched": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"custom_rule_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"custom_rule_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"response": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+// evaluatedCustomRule is a custom rule whose query is parsed
+type evaluatedCustomRule struct {
+	dd.CustomRule
+	expr query.Expr
+}
+
+// dataSourceCustomRuleEvaluationRead evaluates each request against the custom rules in evaluation order,
+// and returns the first custom rule matching it. The custom rules are given by the custom_rule blocks,
+// or listed from the account when there is none. The custom rules which cannot be evaluated are skipped with a warning.
+func dataSourceCustomRuleEvaluationRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	var customRules []dd.CustomRule
+	if blocks := data.Get("custom_rule").([]interface{}); len(blocks) > 0 {
+		for _, v := range blocks {
+			block := v.(map[string]interface{})
+			enabled := block["enabled"].(bool)
+			customRules = append(customRules, dd.CustomRule{
+				Name:         block["name"].(string),
+				Query:        block["query"].(string),
+				Response:     block["response"].(string),
+				Priority:     block["priority"].(string),
+				EndpointType: block["endpoint_type"].(string),
+				Enabled:      &enabled,
+			})
+		}
+	} else {
+		var err error
+		customRules, err = meta.(*ProviderConfig).ClientCustomRule.List(ctx)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	evaluated, diags := orderCustomRules(customRules)
+
+	requests := data.Get("request").([]interface{})
+	results := make([]interface{}, 0, len(requests))
+	var keys []string
+	for _, v := range requests {
+		block := v.(map[string]interface{})
+		endpointType := block["endpoint_type"].(string)
+		request := query.Request{
+			IP:          block["ip"].(string),
+			Domain:      block["domain"].(string),
+			URL:         block["url"].(string),
+			Method:      block["method"].(string),
+			CountryCode: block["country_code"].(string),
+			ASN:         block["asn"].(string),
+			Headers:     map[string]string{},
+		}
+		for name, value := range block["headers"].(map[string]interface{}) {
+			request.Headers[name] = value.(string)
+		}
+
+		result := map[string]interface{}{
+			"matched":          false,
+			"custom_rule_id":   "",
+			"custom_rule_name": "",
+			"response":         "",
+		}
+		for _, customRule := range evaluated {
+			if customRule.EndpointType != "" && customRule.EndpointType != endpointType {
+				continue
+			}
+			matched, err := query.Evaluate(customRule.expr, request)
+			if err != nil {
+				return append(diags, diag.FromErr(err)...)
+			}
+			if !matched {
+				continue
+			}
+			result["matched"] = true
+			if customRule.ID != nil {
+				result["custom_rule_id"] = strconv.Itoa(*customRule.ID)
+			}
+			result["custom_rule_name"] = customRule.Name
+			result["response"] = customRule.Response
+			break
+		}
+		results = append(results, result)
+		keys = append(keys, fmt.Sprintf("%s|%v|%s", endpointType, request, result["custom_rule_name"]))
+	}
+
+	if err := data.Set("result", results); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	data.SetId(strconv.Itoa(schema.HashString(strings.Join(keys, ","))))
+
+	return diags
+}
+
+// orderCustomRules returns the enabled custom rules in evaluation order, from the highest to the lowest priority,
+// the custom rules of the same priority keeping their order. The expired custom rules and the custom rules not activated yet
+// are left out, and the custom rules whose query cannot be parsed or uses a field which cannot be evaluated are skipped with a warning.
+func orderCustomRules(customRules []dd.CustomRule) ([]evaluatedCustomRule, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var evaluated []evaluatedCustomRule
+	for _, customRule := range customRules {
+		if customRule.Enabled != nil && !*customRule.Enabled {
+			continue
+		}
+		if isCustomRuleExpired(customRule.ExpiredAt) || isCustomRuleInactive(customRule.ActivatedAt) {
+			continue
+		}
+		expr, err := query.Parse(customRule.Query)
+		if err == nil {
+			err = query.CheckFields(expr)
+		}
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Custom rule skipped",
+				Detail:   fmt.Sprintf("custom rule %q cannot be evaluated: %s", customRule.Name, err),
+			})
+			continue
+		}
+		evaluated = append(evaluated, evaluatedCustomRule{CustomRule: customRule, expr: expr})
+	}
+
+	sort.SliceStable(evaluated, func(i, j int) bool {
+		return customRulePriorityRanks[evaluated[i].Priority] > customRulePriorityRanks[evaluated[j].Priority]
+	})
+	return evaluated, diags
+}
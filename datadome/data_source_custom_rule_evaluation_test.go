@@ -0,0 +1,35 @@
+package datadome
+
+import (
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderCustomRules(t *testing.T) {
+	disabled := false
+	past, future := "2000-01-01 00:00:00", "2999-01-01 00:00:00"
+	evaluated, diags := orderCustomRules([]dd.CustomRule{
+		{Name: "low", Query: "ip:10.0.0.1", Priority: "low"},
+		{Name: "high-1", Query: "ip:10.0.0.1", Priority: "high"},
+		{Name: "normal", Query: "ip:10.0.0.1", Priority: "normal"},
+		{Name: "disabled", Query: "ip:10.0.0.1", Priority: "high", Enabled: &disabled},
+		{Name: "invalid", Query: "ip:(10.0.0.1", Priority: "high"},
+		{Name: "unsupported", Query: "tls_fingerprint:abc", Priority: "high"},
+		{Name: "expired", Query: "ip:10.0.0.1", Priority: "high", ExpiredAt: &past},
+		{Name: "inactive", Query: "ip:10.0.0.1", Priority: "high", ActivatedAt: &future},
+		{Name: "high-2", Query: "ip:10.0.0.1", Priority: "high", ActivatedAt: &past, ExpiredAt: &future},
+	})
+
+	var names []string
+	for _, customRule := range evaluated {
+		names = append(names, customRule.Name)
+	}
+	assert.Equal(t, []string{"high-1", "high-2", "normal", "low"}, names)
+	if assert.Len(t, diags, 2) {
+		assert.Equal(t, "Custom rule skipped", diags[0].Summary)
+		assert.Contains(t, diags[0].Detail, `custom rule "invalid" cannot be evaluated`)
+		assert.Contains(t, diags[1].Detail, `custom rule "unsupported" cannot be evaluated: unsupported field "tls_fingerprint"`)
+	}
+}
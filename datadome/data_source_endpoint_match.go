@@ -0,0 +1,171 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceEndpointMatch define the read operation and the schema definition to find the endpoints matching sample requests.
+func dataSourceEndpointMatch() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceEndpointMatchRead,
+		Schema: map[string]*schema.Schema{
+			"request": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "/",
+						},
+						"user_agent": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"result": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"path": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"user_agent": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"matched": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"endpoint_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"endpoint_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"indeterminate": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"skipped_before": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+// dataSourceEndpointMatchRead lists the endpoints and returns the first endpoint matching each request, in evaluation order.
+// The endpoints which cannot be evaluated are skipped with a warning. As they may match the requests,
+// the result of a request is indeterminate when one of them is evaluated before the first matching endpoint.
+func dataSourceEndpointMatchRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientEndpoint
+
+	var diags diag.Diagnostics
+
+	endpoints, err := c.List(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// the endpoints which cannot be evaluated keep their position with a nil matcher
+	endpoints = orderEndpoints(endpoints)
+	matchers := make([]*endpointMatcher, len(endpoints))
+	for i, endpoint := range endpoints {
+		matcher, err := newEndpointMatcher(endpoint)
+		if err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Endpoint skipped",
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		matchers[i] = matcher
+	}
+
+	requests := data.Get("request").([]interface{})
+	results := make([]interface{}, 0, len(requests))
+	var keys []string
+	for _, v := range requests {
+		block := v.(map[string]interface{})
+		request := endpointRequest{
+			Host:      block["host"].(string),
+			Path:      block["path"].(string),
+			UserAgent: block["user_agent"].(string),
+		}
+
+		result := map[string]interface{}{
+			"host":          request.Host,
+			"path":          request.Path,
+			"user_agent":    request.UserAgent,
+			"matched":       false,
+			"endpoint_id":   "",
+			"endpoint_name": "",
+		}
+		var skippedBefore []interface{}
+		for i, matcher := range matchers {
+			if matcher == nil {
+				skippedBefore = append(skippedBefore, endpoints[i].Name)
+				continue
+			}
+			if !matcher.Matches(request) {
+				continue
+			}
+			if len(skippedBefore) == 0 {
+				result["matched"] = true
+				if matcher.Endpoint.ID != nil {
+					result["endpoint_id"] = *matcher.Endpoint.ID
+				}
+				result["endpoint_name"] = matcher.Endpoint.Name
+			}
+			break
+		}
+		result["indeterminate"] = len(skippedBefore) > 0
+		result["skipped_before"] = skippedBefore
+		results = append(results, result)
+		keys = append(keys, fmt.Sprintf("%s%s|%s|%s", request.Host, request.Path, request.UserAgent, result["endpoint_id"]))
+	}
+
+	if err = data.Set("result", results); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	data.SetId(strconv.Itoa(schema.HashString(strings.Join(keys, ","))))
+
+	return diags
+}
@@ -0,0 +1,114 @@
+package datadome
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/datadome/terraform-provider/common"
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceVerifiedBots define the read operation and the schema definition to look up DataDome Verified Bots and AI Agents.
+func dataSourceVerifiedBots() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVerifiedBotsRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"category": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"verified_bots": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"category": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+// dataSourceVerifiedBotsRead lists the verified bots matching the name and category filters, compared case-insensitively
+func dataSourceVerifiedBotsRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientVerifiedBot
+
+	name := data.Get("name").(string)
+	category := data.Get("category").(string)
+
+	verifiedBots, err := c.List(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var uuids []string
+	result := make([]interface{}, 0, len(verifiedBots))
+	for _, verifiedBot := range verifiedBots {
+		if name != "" && !strings.EqualFold(verifiedBot.Name, name) {
+			continue
+		}
+		if category != "" && !strings.EqualFold(verifiedBot.Category, category) {
+			continue
+		}
+		uuids = append(uuids, verifiedBot.UUID)
+		result = append(result, flattenVerifiedBot(verifiedBot))
+	}
+
+	if err = data.Set("verified_bots", result); err != nil {
+		return diag.FromErr(err)
+	}
+
+	data.SetId(strconv.Itoa(schema.HashString(strings.Join(uuids, ","))))
+
+	return nil
+}
+
+// flattenVerifiedBot converts a dd.VerifiedBot into the map representation expected by the Terraform schema
+func flattenVerifiedBot(verifiedBot dd.VerifiedBot) map[string]interface{} {
+	return map[string]interface{}{
+		"uuid":     verifiedBot.UUID,
+		"name":     verifiedBot.Name,
+		"category": verifiedBot.Category,
+	}
+}
+
+// findVerifiedBot returns the verified bot with the given UUID, or nil when it does not exist
+func findVerifiedBot(ctx context.Context, c common.ListAPI[dd.VerifiedBot], uuid string) (*dd.VerifiedBot, error) {
+	verifiedBots, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, verifiedBot := range verifiedBots {
+		if strings.EqualFold(verifiedBot.UUID, uuid) {
+			return &verifiedBot, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,108 @@
+package datadome
+
+import (
+	"fmt"
+	"regexp"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+)
+
+// endpointRequest is a sample request matched against the endpoints
+type endpointRequest struct {
+	Host      string
+	Path      string
+	UserAgent string
+}
+
+// orderEndpoints returns the endpoints in evaluation order: the endpoints keep the order of the API,
+// except the endpoints with a position_before, which are moved right before the endpoint they reference.
+// The endpoints referencing an unknown endpoint, or part of a cycle, are evaluated last.
+func orderEndpoints(endpoints []dd.Endpoint) []dd.Endpoint {
+	ids := map[string]bool{}
+	for _, endpoint := range endpoints {
+		if endpoint.ID != nil {
+			ids[*endpoint.ID] = true
+		}
+	}
+
+	var ordered, pending []dd.Endpoint
+	for _, endpoint := range endpoints {
+		if endpoint.PositionBefore != nil && *endpoint.PositionBefore != "" && ids[*endpoint.PositionBefore] {
+			pending = append(pending, endpoint)
+		} else {
+			ordered = append(ordered, endpoint)
+		}
+	}
+
+	for len(pending) > 0 {
+		var remaining []dd.Endpoint
+		for _, endpoint := range pending {
+			position := -1
+			for i, other := range ordered {
+				if other.ID != nil && *other.ID == *endpoint.PositionBefore {
+					position = i
+					break
+				}
+			}
+			if position < 0 {
+				remaining = append(remaining, endpoint)
+				continue
+			}
+			ordered = append(ordered[:position], append([]dd.Endpoint{endpoint}, ordered[position:]...)...)
+		}
+		if len(remaining) == len(pending) {
+			return append(ordered, remaining...)
+		}
+		pending = remaining
+	}
+	return ordered
+}
+
+// endpointMatcher matches the requests against the regular expressions of an endpoint
+type endpointMatcher struct {
+	Endpoint           dd.Endpoint
+	domain             *regexp.Regexp
+	pathInclusion      *regexp.Regexp
+	pathExclusion      *regexp.Regexp
+	userAgentInclusion *regexp.Regexp
+}
+
+// newEndpointMatcher compiles the regular expressions of an endpoint.
+// It raises an error when a regular expression is invalid or uses PCRE constructs which Go cannot evaluate,
+// or when the endpoint is defined by a query.
+func newEndpointMatcher(endpoint dd.Endpoint) (*endpointMatcher, error) {
+	if endpoint.Query != nil && *endpoint.Query != "" {
+		return nil, fmt.Errorf("endpoint %q is defined by a query, which cannot be evaluated", endpoint.Name)
+	}
+
+	matcher := &endpointMatcher{Endpoint: endpoint}
+	for _, field := range []struct {
+		name   string
+		value  *string
+		target **regexp.Regexp
+	}{
+		{"domain", endpoint.Domain, &matcher.domain},
+		{"path_inclusion", endpoint.PathInclusion, &matcher.pathInclusion},
+		{"path_exclusion", endpoint.PathExclusion, &matcher.pathExclusion},
+		{"user_agent_inclusion", endpoint.UserAgentInclusion, &matcher.userAgentInclusion},
+	} {
+		if field.value == nil || *field.value == "" {
+			continue
+		}
+		pattern, err := evaluableEndpointRegex(*field.value)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %q: %s: %w", endpoint.Name, field.name, err)
+		}
+		*field.target = regexp.MustCompile(pattern)
+	}
+	return matcher, nil
+}
+
+// Matches reports whether a request matches the domain, the path inclusion and the user agent inclusion of the endpoint,
+// and does not match its path exclusion. An empty regular expression matches all the requests.
+func (m *endpointMatcher) Matches(request endpointRequest) bool {
+	return (m.domain == nil || m.domain.MatchString(request.Host)) &&
+		(m.pathInclusion == nil || m.pathInclusion.MatchString(request.Path)) &&
+		(m.pathExclusion == nil || !m.pathExclusion.MatchString(request.Path)) &&
+		(m.userAgentInclusion == nil || m.userAgentInclusion.MatchString(request.UserAgent))
+}
@@ -0,0 +1,109 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func testEndpoint(id, positionBefore string) dd.Endpoint {
+	endpoint := dd.Endpoint{ID: &id, Name: id}
+	if positionBefore != "" {
+		endpoint.PositionBefore = &positionBefore
+	}
+	return endpoint
+}
+
+func TestOrderEndpoints(t *testing.T) {
+	names := func(endpoints []dd.Endpoint) []string {
+		var result []string
+		for _, endpoint := range endpoints {
+			result = append(result, endpoint.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"a", "b", "c"}, names(orderEndpoints([]dd.Endpoint{
+		testEndpoint("a", ""), testEndpoint("b", ""), testEndpoint("c", ""),
+	})))
+
+	assert.Equal(t, []string{"b", "c", "a", "default"}, names(orderEndpoints([]dd.Endpoint{
+		testEndpoint("default", ""), testEndpoint("a", "default"), testEndpoint("b", "c"), testEndpoint("c", "a"),
+	})))
+
+	// an unknown reference and a cycle are evaluated last
+	assert.Equal(t, []string{"default", "a", "b", "c"}, names(orderEndpoints([]dd.Endpoint{
+		testEndpoint("default", ""), testEndpoint("a", "unknown"), testEndpoint("b", "c"), testEndpoint("c", "b"),
+	})))
+}
+
+func TestEndpointMatcher(t *testing.T) {
+	domain, pathInclusion, pathExclusion, userAgent := `^(www\.)?example\.com$`, `^/api/`, `^/api/health`, `MyApp/`
+	matcher, err := newEndpointMatcher(dd.Endpoint{
+		Name:               "api",
+		Domain:             &domain,
+		PathInclusion:      &pathInclusion,
+		PathExclusion:      &pathExclusion,
+		UserAgentInclusion: &userAgent,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.True(t, matcher.Matches(endpointRequest{Host: "www.example.com", Path: "/api/login", UserAgent: "MyApp/1.0"}))
+	assert.False(t, matcher.Matches(endpointRequest{Host: "example.org", Path: "/api/login", UserAgent: "MyApp/1.0"}))
+	assert.False(t, matcher.Matches(endpointRequest{Host: "example.com", Path: "/login", UserAgent: "MyApp/1.0"}))
+	assert.False(t, matcher.Matches(endpointRequest{Host: "example.com", Path: "/api/health", UserAgent: "MyApp/1.0"}))
+	assert.False(t, matcher.Matches(endpointRequest{Host: "example.com", Path: "/api/login", UserAgent: "curl/8.0"}))
+
+	matcher, err = newEndpointMatcher(dd.Endpoint{Name: "default"})
+	if assert.NoError(t, err) {
+		assert.True(t, matcher.Matches(endpointRequest{Host: "example.com", Path: "/"}))
+	}
+
+	invalid := "("
+	_, err = newEndpointMatcher(dd.Endpoint{Name: "invalid", PathInclusion: &invalid})
+	assert.EqualError(t, err, `endpoint "invalid": path_inclusion: missing closing ) in "("`)
+
+	lookahead := "^/(?!admin)"
+	_, err = newEndpointMatcher(dd.Endpoint{Name: "lookahead", PathInclusion: &lookahead})
+	assert.ErrorIs(t, err, errEndpointRegexNotEvaluable)
+
+	query := "url:*api*"
+	_, err = newEndpointMatcher(dd.Endpoint{Name: "query", Query: &query})
+	assert.EqualError(t, err, `endpoint "query" is defined by a query, which cannot be evaluated`)
+}
+
+func TestDataSourceEndpointMatchRead_skippedEndpoint(t *testing.T) {
+	mockClient := dd.NewMockClientEndpoint()
+	query, pathInclusion, domain := "Host = 'shop.example.com'", `^/login`, `example\.com$`
+	login, shop, fallback := testEndpoint("login", ""), testEndpoint("shop", ""), testEndpoint("default", "")
+	login.PathInclusion, shop.Query, fallback.Domain = &pathInclusion, &query, &domain
+	mockClient.ListFunc = func(ctx context.Context) ([]dd.Endpoint, error) {
+		return []dd.Endpoint{login, shop, fallback}, nil
+	}
+	data := schema.TestResourceDataRaw(t, dataSourceEndpointMatch().Schema, map[string]interface{}{
+		"request": []interface{}{
+			map[string]interface{}{"host": "shop.example.com", "path": "/login"},
+			map[string]interface{}{"host": "shop.example.com", "path": "/cart"},
+		},
+	})
+
+	diags := dataSourceEndpointMatchRead(context.Background(), data, &ProviderConfig{ClientEndpoint: mockClient})
+
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, "Endpoint skipped", diags[0].Summary)
+	}
+	// the login endpoint is evaluated before the endpoint defined by a query
+	assert.Equal(t, true, data.Get("result.0.matched"))
+	assert.Equal(t, "login", data.Get("result.0.endpoint_name"))
+	assert.Equal(t, false, data.Get("result.0.indeterminate"))
+	// the endpoint defined by a query may match the request before the default endpoint
+	assert.Equal(t, false, data.Get("result.1.matched"))
+	assert.Equal(t, "", data.Get("result.1.endpoint_name"))
+	assert.Equal(t, true, data.Get("result.1.indeterminate"))
+	assert.Equal(t, []interface{}{"shop"}, data.Get("result.1.skipped_before"))
+}
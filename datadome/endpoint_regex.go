@@ -0,0 +1,508 @@
+package datadome
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// The regular expressions of the endpoints are evaluated by the server with PCRE.
+// They are validated by translating them into Go regular expressions: the PCRE constructs with a Go equivalent are rewritten,
+// and the constructs without one, such as lookarounds or backreferences, are replaced by a placeholder of the same shape,
+// so that the Go parser checks the syntax of the rest of the expression.
+// The regular expressions with placeholders are valid, but cannot be evaluated by the provider.
+
+// endpointRegexGroupConstructs lists the groups starting with "(?" which Go does not support, and their placeholder
+var endpointRegexGroupConstructs = []struct {
+	prefix      string
+	description string
+}{
+	{"(?=", "lookaheads"},
+	{"(?!", "negative lookaheads"},
+	{"(?<=", "lookbehinds"},
+	{"(?<!", "negative lookbehinds"},
+	{"(?>", "atomic groups"},
+	{"(?|", "branch reset groups"},
+	{"(?(", "conditional groups"},
+}
+
+// endpointRegexEscapes lists the escape sequences which Go does not support, outside of a character class,
+// with their Go equivalent, or an empty equivalent when there is none
+var endpointRegexEscapes = map[byte]struct {
+	description string
+	equivalent  string
+}{
+	'h': {"horizontal whitespace classes", `[` + endpointRegexHorizontalSpaces + `]`},
+	'H': {"horizontal whitespace classes", `[^` + endpointRegexHorizontalSpaces + `]`},
+	'v': {"vertical whitespace classes", `[` + endpointRegexVerticalSpaces + `]`},
+	'V': {"vertical whitespace classes", `[^` + endpointRegexVerticalSpaces + `]`},
+	'R': {"newline sequences", `(?:\r\n|[` + endpointRegexVerticalSpaces + `])`},
+	'N': {"non-newline characters", `[^\n]`},
+	'e': {"escape characters", `\x1B`},
+	'K': {"match resets", ""},
+	'G': {"match start anchors", ""},
+	'Z': {`\Z anchors`, ""},
+	'X': {"extended grapheme clusters", ""},
+	'C': {"single code units", ""},
+}
+
+const (
+	endpointRegexHorizontalSpaces = `\t \x{A0}\x{1680}\x{180E}\x{2000}-\x{200A}\x{202F}\x{205F}\x{3000}`
+	endpointRegexVerticalSpaces   = `\n\x0B\f\r\x{85}\x{2028}\x{2029}`
+)
+
+// errEndpointRegexNotEvaluable is raised on the regular expressions using PCRE constructs which the provider cannot evaluate
+var errEndpointRegexNotEvaluable = errors.New("cannot be evaluated by the provider")
+
+// endpointRegexCountedRepetition matches the end of a counted repetition, such as {2,5}
+var endpointRegexCountedRepetition = regexp.MustCompile(`\{\d+(,\d*)?\}$`)
+
+// endpointRegexConstruct is a PCRE construct of a regular expression which Go regular expressions cannot evaluate
+type endpointRegexConstruct struct {
+	text        string
+	offset      int
+	description string
+}
+
+func (c endpointRegexConstruct) String() string {
+	return fmt.Sprintf("%s (%q at offset %d)", c.description, c.text, c.offset)
+}
+
+// endpointRegexTranslation is the translation of a PCRE regular expression into a Go regular expression
+type endpointRegexTranslation struct {
+	// pattern is the Go regular expression, equivalent to the PCRE regular expression when it has no constructs
+	pattern string
+	// constructs are the PCRE constructs replaced by a placeholder, in the order of the expression
+	constructs []endpointRegexConstruct
+	// backreference is the backreference to the capturing group of highest number, if any
+	backreference *endpointRegexConstruct
+	group         int
+}
+
+// validateEndpointRegex validates a regular expression of an endpoint against PCRE, the dialect of the server.
+// It raises an error on the invalid expressions, and a warning on the nested quantifiers, such as (a+)+,
+// which can take an exponential time to fail on a backtracking engine.
+// The PCRE constructs which Go does not support, such as lookarounds or backreferences, are accepted.
+func validateEndpointRegex(i interface{}, path cty.Path) diag.Diagnostics {
+	value, ok := i.(string)
+	if !ok {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid regular expression",
+			Detail:        fmt.Sprintf("expected type to be string, got %T", i),
+			AttributePath: path,
+		}}
+	}
+
+	re, err := parseEndpointRegex(value)
+	if err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid regular expression",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+
+	if nested := findNestedQuantifier(re); nested != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Catastrophic backtracking risk",
+			Detail: fmt.Sprintf("%q repeats %q, which is already repeated: nested quantifiers can take an exponential time to fail on a backtracking engine. "+
+				"Remove the inner or the outer quantifier.", nested.String(), nested.Sub[0].String()),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
+
+// parseEndpointRegex checks the PCRE syntax of a regular expression, and returns the parsed Go translation of the expression
+func parseEndpointRegex(pattern string) (*syntax.Regexp, error) {
+	translation, err := translateEndpointRegex(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := syntax.Parse(translation.pattern, syntax.Perl)
+	if err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			return nil, fmt.Errorf("%s in %q", syntaxErr.Code, pattern)
+		}
+		return nil, err
+	}
+	if _, err = regexp.Compile(translation.pattern); err != nil {
+		return nil, err
+	}
+
+	if ref := translation.backreference; ref != nil && translation.group > re.MaxCap() {
+		return nil, fmt.Errorf("%q at offset %d of %q refers to the capturing group %d, which does not exist", ref.text, ref.offset, pattern, translation.group)
+	}
+	return re, nil
+}
+
+// evaluableEndpointRegex returns the Go regular expression equivalent to a PCRE regular expression of an endpoint,
+// to evaluate it in the provider. It raises an error when the expression uses PCRE constructs Go cannot evaluate.
+func evaluableEndpointRegex(pattern string) (string, error) {
+	if _, err := parseEndpointRegex(pattern); err != nil {
+		return "", err
+	}
+	translation, err := translateEndpointRegex(pattern)
+	if err != nil {
+		return "", err
+	}
+	if len(translation.constructs) > 0 {
+		return "", fmt.Errorf("%s %w", translation.constructs[0], errEndpointRegexNotEvaluable)
+	}
+	return translation.pattern, nil
+}
+
+// translateEndpointRegex translates a PCRE regular expression into a Go regular expression, see endpointRegexTranslation.
+// It raises an error on the PCRE syntax errors which the Go parser cannot detect in the translation.
+func translateEndpointRegex(pattern string) (*endpointRegexTranslation, error) {
+	translation := &endpointRegexTranslation{}
+	var out strings.Builder
+	// the start of the translation of each open group, -1 for the groups which are not lookbehinds
+	var lookbehinds []int
+	construct := func(text string, offset int, description string) {
+		translation.constructs = append(translation.constructs, endpointRegexConstruct{text: text, offset: offset, description: description})
+	}
+	// closing returns the index of the next ")", or the end of the pattern
+	closing := func(from int) int {
+		if end := strings.IndexByte(pattern[from:], ')'); end >= 0 {
+			return from + end
+		}
+		return len(pattern) - 1
+	}
+
+	inClass := false
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			next := pattern[i+1]
+			switch {
+			case next == 'Q':
+				// the characters between \Q and \E are literals
+				end := strings.Index(pattern[i+2:], `\E`)
+				if end < 0 {
+					out.WriteString(pattern[i:])
+					i = len(pattern)
+					continue
+				}
+				out.WriteString(pattern[i : i+2+end+2])
+				i += end + 3
+			case next == 'c' && i+2 < len(pattern):
+				fmt.Fprintf(&out, `\x{%X}`, (pattern[i+2]&^0x20)^0x40)
+				i += 2
+			case next == 'o' && strings.HasPrefix(pattern[i+2:], "{"):
+				end := strings.IndexByte(pattern[i:], '}')
+				code, err := strconv.ParseUint(pattern[i+3:i+max(end, 3)], 8, 32)
+				if end < 0 || err != nil {
+					return nil, fmt.Errorf("invalid octal escape at offset %d of %q", i, pattern)
+				}
+				fmt.Fprintf(&out, `\x{%X}`, code)
+				i += end
+			case next >= '1' && next <= '9' && !inClass:
+				end := i + 2
+				for end < len(pattern) && pattern[end] >= '0' && pattern[end] <= '9' {
+					end++
+				}
+				group, _ := strconv.Atoi(pattern[i+1 : end])
+				translation.addBackreference(pattern[i:end], i, group)
+				construct(pattern[i:end], i, "backreferences")
+				out.WriteString("(?:)")
+				i = end - 1
+			case next == 'k' || next == 'g':
+				end := i + 2
+				if end < len(pattern) {
+					if closer, found := map[byte]byte{'<': '>', '{': '}', '\'': '\''}[pattern[end]]; found {
+						if length := strings.IndexByte(pattern[end+1:], closer); length >= 0 {
+							end += length + 2
+						}
+					} else {
+						for end < len(pattern) && (pattern[end] == '-' || pattern[end] == '+' || pattern[end] >= '0' && pattern[end] <= '9') {
+							end++
+						}
+					}
+				}
+				reference := strings.Trim(pattern[i+2:end], "{}")
+				if group, err := strconv.Atoi(reference); err == nil && group > 0 {
+					translation.addBackreference(pattern[i:end], i, group)
+				}
+				description := "backreferences"
+				if next == 'g' && end > i+2 && (pattern[i+2] == '<' || pattern[i+2] == '\'') {
+					description = "subroutine calls"
+				}
+				construct(pattern[i:end], i, description)
+				out.WriteString("(?:)")
+				i = end - 1
+			default:
+				escape, found := endpointRegexEscapes[next]
+				switch {
+				case !found:
+					out.WriteString(pattern[i : i+2])
+				case inClass && (next == 'h' || next == 'v'):
+					out.WriteString(strings.Trim(escape.equivalent, "[]"))
+				case inClass && next == 'e':
+					out.WriteString(escape.equivalent)
+				case inClass:
+					return nil, fmt.Errorf("%q at offset %d of %q is not supported in a character class", pattern[i:i+2], i, pattern)
+				case escape.equivalent != "":
+					out.WriteString(escape.equivalent)
+				default:
+					construct(pattern[i:i+2], i, escape.description)
+					out.WriteString("(?:)")
+				}
+				i++
+			}
+		case inClass:
+			if c == ']' {
+				inClass = false
+			} else if strings.HasPrefix(pattern[i:], "[:") {
+				// skip the POSIX classes, such as [:alpha:]
+				if end := strings.Index(pattern[i+2:], ":]"); end >= 0 {
+					out.WriteString(pattern[i : i+end+4])
+					i += end + 3
+					continue
+				}
+			}
+			out.WriteByte(c)
+		case c == '[':
+			inClass = true
+			out.WriteByte(c)
+			// a "]" right after "[" or "[^" is a literal
+			if strings.HasPrefix(pattern[i+1:], "^") {
+				out.WriteByte('^')
+				i++
+			}
+			if strings.HasPrefix(pattern[i+1:], "]") {
+				out.WriteByte(']')
+				i++
+			}
+		case c == '(' && strings.HasPrefix(pattern[i:], "(*"):
+			// backtracking control verbs, such as (*SKIP) or (*UTF)
+			end := closing(i)
+			construct(pattern[i:end+1], i, "backtracking control verbs")
+			i = end
+		case c == '(' && strings.HasPrefix(pattern[i:], "(?"):
+			group := translation.translateGroup(pattern, i, &out, construct, closing)
+			if group.lookbehind {
+				lookbehinds = append(lookbehinds, out.Len())
+			} else if group.open {
+				lookbehinds = append(lookbehinds, -1)
+			}
+			i = group.end
+		case c == '(':
+			lookbehinds = append(lookbehinds, -1)
+			out.WriteByte(c)
+		case c == ')':
+			if len(lookbehinds) > 0 {
+				start := lookbehinds[len(lookbehinds)-1]
+				lookbehinds = lookbehinds[:len(lookbehinds)-1]
+				if start >= 0 {
+					if body, err := syntax.Parse(out.String()[start:], syntax.Perl); err == nil && repeatsRune(body, 0, false) {
+						return nil, fmt.Errorf("the lookbehind ending at offset %d of %q is not of bounded length", i, pattern)
+					}
+				}
+			}
+			out.WriteByte(c)
+		case (c == '*' || c == '+' || c == '?' || c == '}') && i+1 < len(pattern) && pattern[i+1] == '+':
+			out.WriteByte(c)
+			// "}" is a literal when it does not end a counted repetition
+			if c == '}' && !endpointRegexCountedRepetition.MatchString(pattern[:i+1]) {
+				continue
+			}
+			construct(pattern[i:i+2], i, "possessive quantifiers")
+			i++
+		default:
+			out.WriteByte(c)
+		}
+	}
+
+	translation.pattern = out.String()
+	return translation, nil
+}
+
+// addBackreference records a backreference, keeping the one to the capturing group of highest number
+func (t *endpointRegexTranslation) addBackreference(text string, offset, group int) {
+	if group > t.group {
+		t.group = group
+		t.backreference = &endpointRegexConstruct{text: text, offset: offset, description: "backreferences"}
+	}
+}
+
+// endpointRegexGroup is the translation of the start of a group beginning with "(?"
+type endpointRegexGroup struct {
+	// end is the offset of the last character of the start of the group in the pattern
+	end int
+	// open reports whether the group stays open, and lookbehind whether it is a lookbehind
+	open, lookbehind bool
+}
+
+// translateGroup translates the start of the group beginning with "(?" at offset i of the pattern
+func (t *endpointRegexTranslation) translateGroup(pattern string, i int, out *strings.Builder, construct func(string, int, string), closing func(int) int) endpointRegexGroup {
+	rest := pattern[i:]
+	switch {
+	case strings.HasPrefix(rest, "(?#"):
+		// comments are removed
+		return endpointRegexGroup{end: closing(i)}
+	case strings.HasPrefix(rest, "(?'"):
+		// named groups with quotes are rewritten with angle brackets
+		if end := strings.IndexByte(rest[3:], '\''); end >= 0 {
+			out.WriteString("(?P<" + rest[3:3+end] + ">")
+			return endpointRegexGroup{end: i + 3 + end, open: true}
+		}
+	case strings.HasPrefix(rest, "(?<") && !strings.HasPrefix(rest, "(?<=") && !strings.HasPrefix(rest, "(?<!"),
+		strings.HasPrefix(rest, "(?P<"):
+		out.WriteString("(?")
+		return endpointRegexGroup{end: i + 1, open: true}
+	case strings.HasPrefix(rest, "(?P="):
+		end := closing(i)
+		construct(pattern[i:end+1], i, "named backreferences")
+		out.WriteString("(?:)")
+		return endpointRegexGroup{end: end}
+	case strings.HasPrefix(rest, "(?R)"), strings.HasPrefix(rest, "(?&"), strings.HasPrefix(rest, "(?P>"),
+		len(rest) > 2 && (rest[2] == '+' || rest[2] == '-' || rest[2] >= '0' && rest[2] <= '9') && !strings.HasPrefix(rest, "(?-") || strings.HasPrefix(rest, "(?-") && len(rest) > 3 && rest[3] >= '0' && rest[3] <= '9':
+		end := closing(i)
+		construct(pattern[i:end+1], i, "recursions and subroutine calls")
+		out.WriteString("(?:)")
+		return endpointRegexGroup{end: end}
+	}
+
+	for _, group := range endpointRegexGroupConstructs {
+		if !strings.HasPrefix(rest, group.prefix) {
+			continue
+		}
+		construct(group.prefix, i, group.description)
+		out.WriteString("(?:")
+		end := i + len(group.prefix) - 1
+		// the condition of a conditional group is removed, unless it is a lookaround
+		if group.prefix == "(?(" && !strings.HasPrefix(rest, "(?(?") {
+			end = closing(i + 3)
+		}
+		return endpointRegexGroup{end: end, open: true, lookbehind: strings.HasPrefix(group.prefix, "(?<")}
+	}
+
+	// inline options, such as (?i) or (?x:...), keeping the options supported by Go
+	end := i + 2
+	for end < len(pattern) && strings.IndexByte("imsxnJUX-^", pattern[end]) >= 0 {
+		end++
+	}
+	if end < len(pattern) && (pattern[end] == ')' || pattern[end] == ':') {
+		options := pattern[i+2 : end]
+		supported := strings.Map(func(r rune) rune {
+			if strings.ContainsRune("imsU-", r) {
+				return r
+			}
+			return -1
+		}, options)
+		if supported != options {
+			construct(pattern[i:end+1], i, "inline options other than i, m, s and U")
+		}
+		supported = strings.TrimSuffix(supported, "-")
+		if supported == "" {
+			supported = ":"
+			if pattern[end] == ')' {
+				supported = ":)"
+			}
+		} else {
+			supported += string(pattern[end])
+		}
+		out.WriteString("(?" + supported)
+		return endpointRegexGroup{end: end, open: pattern[end] == ':'}
+	}
+
+	out.WriteString("(?")
+	return endpointRegexGroup{end: i + 1, open: true}
+}
+
+// findNestedQuantifier returns the first unbounded repetition of a regular expression containing another
+// unbounded repetition, or nil when there is none. The repetitions whose operand starts with a literal character
+// which the inner repetitions cannot match, such as (/[a-z]+)*, are not ambiguous and are ignored.
+func findNestedQuantifier(re *syntax.Regexp) *syntax.Regexp {
+	if isUnboundedRepetition(re) {
+		first, ok := leadingRune(re.Sub[0])
+		if repeatsRune(re.Sub[0], first, ok) {
+			return re
+		}
+	}
+	for _, sub := range re.Sub {
+		if nested := findNestedQuantifier(sub); nested != nil {
+			return nested
+		}
+	}
+	return nil
+}
+
+// leadingRune returns the literal character starting all the strings matched by a regular expression, if any
+func leadingRune(re *syntax.Regexp) (rune, bool) {
+	for re.Op == syntax.OpCapture || re.Op == syntax.OpConcat {
+		if len(re.Sub) == 0 {
+			return 0, false
+		}
+		re = re.Sub[0]
+	}
+	if re.Op != syntax.OpLiteral || re.Flags&syntax.FoldCase != 0 || len(re.Rune) == 0 {
+		return 0, false
+	}
+	return re.Rune[0], true
+}
+
+// repeatsRune reports whether a regular expression contains an unbounded repetition which can match the given character,
+// or any unbounded repetition when the character is not known
+func repeatsRune(re *syntax.Regexp, r rune, known bool) bool {
+	if isUnboundedRepetition(re) && (!known || matchesRune(re.Sub[0], r)) {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if repeatsRune(sub, r, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesRune reports whether a regular expression can start with the given character, assuming it can when unsure
+func matchesRune(re *syntax.Regexp, r rune) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		if len(re.Rune) == 0 {
+			return true
+		}
+		if re.Flags&syntax.FoldCase != 0 {
+			return strings.EqualFold(string(re.Rune[0]), string(r))
+		}
+		return re.Rune[0] == r
+	case syntax.OpCharClass:
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			if re.Rune[i] <= r && r <= re.Rune[i+1] {
+				return true
+			}
+		}
+		return false
+	case syntax.OpAnyCharNotNL:
+		return r != '\n'
+	case syntax.OpCapture, syntax.OpPlus:
+		return matchesRune(re.Sub[0], r)
+	case syntax.OpConcat:
+		if len(re.Sub) > 0 {
+			first := re.Sub[0]
+			if first.Op == syntax.OpLiteral || first.Op == syntax.OpCharClass || first.Op == syntax.OpAnyCharNotNL {
+				return matchesRune(first, r)
+			}
+		}
+	}
+	return true
+}
+
+// isUnboundedRepetition reports whether a regular expression repeats its operand without upper bound, such as a* or a{2,}
+func isUnboundedRepetition(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpStar || re.Op == syntax.OpPlus || (re.Op == syntax.OpRepeat && re.Max == -1)
+}
@@ -0,0 +1,109 @@
+package datadome
+
+import (
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateEndpointRegex(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		severity diag.Severity
+		summary  string
+		detail   string
+	}{
+		{pattern: `^/api/v[0-9]+/`},
+		{pattern: `(^|\.)example\.com$`},
+		{pattern: `^/(?P<version>v[0-9]+)/`},
+		{pattern: `^[[:alpha:]]+\.example\.com$`},
+		{pattern: `^\Q/(?=a)\E`},
+		{pattern: `^x}+`},
+		{pattern: `^(/[a-z]+)*$`},
+		{pattern: `^/(?!admin)`},
+		{pattern: `(?<=/)api`},
+		{pattern: `^/(a)\1`},
+		{pattern: `^/(?<name>a)\k<name>`},
+		{pattern: `^/a{2}+`},
+		{pattern: `^/(?>a+)b`},
+		{pattern: `[\v]\h(?#comment)`},
+		{
+			pattern:  `(?<=/a+)api`,
+			severity: diag.Error,
+			summary:  "Invalid regular expression",
+			detail:   `the lookbehind ending at offset 7 of "(?<=/a+)api" is not of bounded length`,
+		},
+		{
+			pattern:  `^/(a)\2`,
+			severity: diag.Error,
+			summary:  "Invalid regular expression",
+			detail:   `"\\2" at offset 5 of "^/(a)\\2" refers to the capturing group 2, which does not exist`,
+		},
+		{
+			pattern:  `^/(?=a`,
+			severity: diag.Error,
+			summary:  "Invalid regular expression",
+			detail:   `missing closing ) in "^/(?=a"`,
+		},
+		{
+			pattern:  `wrong(.*`,
+			severity: diag.Error,
+			summary:  "Invalid regular expression",
+			detail:   `missing closing ) in "wrong(.*"`,
+		},
+		{
+			pattern:  `^(a+)+$`,
+			severity: diag.Warning,
+			summary:  "Catastrophic backtracking risk",
+			detail:   `"(a+)+" repeats "(a+)", which is already repeated: nested quantifiers can take an exponential time to fail on a backtracking engine. Remove the inner or the outer quantifier.`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			diags := validateEndpointRegex(test.pattern, cty.GetAttrPath("path_inclusion"))
+			if test.summary == "" {
+				assert.Empty(t, diags)
+				return
+			}
+			if assert.Len(t, diags, 1) {
+				assert.Equal(t, test.severity, diags[0].Severity)
+				assert.Equal(t, test.summary, diags[0].Summary)
+				assert.Equal(t, test.detail, diags[0].Detail)
+			}
+		})
+	}
+}
+
+func TestEvaluableEndpointRegex(t *testing.T) {
+	tests := map[string]string{
+		`^/api/\e\cA\o{101}$`:   `^/api/\x1B\x{1}\x{41}$`,
+		`^(?'version'v\d+)\R`:   `^(?P<version>v\d+)(?:\r\n|[` + endpointRegexVerticalSpaces + `])`,
+		`(?i)^/api(?#comment)/`: `(?i)^/api/`,
+	}
+	for pattern, expected := range tests {
+		actual, err := evaluableEndpointRegex(pattern)
+		if assert.NoError(t, err, pattern) {
+			assert.Equal(t, expected, actual, pattern)
+		}
+	}
+
+	_, err := evaluableEndpointRegex(`^/(a)(?!b)\1`)
+	assert.EqualError(t, err, `negative lookaheads ("(?!" at offset 5) cannot be evaluated by the provider`)
+}
+
+func TestFindNestedQuantifier(t *testing.T) {
+	tests := map[string]bool{
+		`^(\w+\s?)*$`:   true,
+		`(.*)*x`:        true,
+		`(x.*)+`:        true,
+		`^(/[a-z]+)*$`:  false,
+		`^(ab){1,3}$`:   false,
+		`^(a+){2}$`:     false,
+		`^/api/.*\.js$`: false,
+	}
+	for pattern, expected := range tests {
+		assert.Equal(t, expected, findNestedQuantifier(testParseRegex(t, pattern)) != nil, pattern)
+	}
+}
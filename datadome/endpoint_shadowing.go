@@ -0,0 +1,446 @@
+package datadome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp/syntax"
+	"strings"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// endpointConstraints are the parsed constraints of an endpoint, a nil regular expression matching all the requests
+type endpointConstraints struct {
+	domain             *syntax.Regexp
+	pathInclusion      *syntax.Regexp
+	pathExclusion      *syntax.Regexp
+	userAgentInclusion *syntax.Regexp
+	query              query.Expr
+}
+
+// parseEndpointConstraints parses the regular expressions or the query of an endpoint
+func parseEndpointConstraints(endpoint dd.Endpoint) (*endpointConstraints, error) {
+	constraints := &endpointConstraints{}
+	if endpoint.Query != nil && *endpoint.Query != "" {
+		expr, err := query.Parse(*endpoint.Query)
+		if err != nil {
+			return nil, err
+		}
+		constraints.query = expr
+		return constraints, nil
+	}
+
+	for _, field := range []struct {
+		value  *string
+		target **syntax.Regexp
+	}{
+		{endpoint.Domain, &constraints.domain},
+		{endpoint.PathInclusion, &constraints.pathInclusion},
+		{endpoint.PathExclusion, &constraints.pathExclusion},
+		{endpoint.UserAgentInclusion, &constraints.userAgentInclusion},
+	} {
+		if field.value == nil || *field.value == "" {
+			continue
+		}
+		pattern, err := evaluableEndpointRegex(*field.value)
+		if err != nil {
+			return nil, err
+		}
+		re, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			return nil, err
+		}
+		*field.target = re
+	}
+	return constraints, nil
+}
+
+// alternatives splits the constraints into the alternatives of the path inclusion, or of the query,
+// with a description of each alternative
+func (c *endpointConstraints) alternatives() ([]*endpointConstraints, []string) {
+	var alternatives []*endpointConstraints
+	var descriptions []string
+	if c.query != nil {
+		operands := []query.Expr{c.query}
+		if or, ok := c.query.(query.Or); ok {
+			operands = or.Operands
+		}
+		for _, operand := range operands {
+			alternatives = append(alternatives, &endpointConstraints{query: operand})
+			descriptions = append(descriptions, operand.String())
+		}
+		return alternatives, descriptions
+	}
+
+	for _, pathInclusion := range regexAlternatives(c.pathInclusion) {
+		alternative := *c
+		alternative.pathInclusion = pathInclusion
+		alternatives = append(alternatives, &alternative)
+		descriptions = append(descriptions, describeRegex(pathInclusion))
+	}
+	return alternatives, descriptions
+}
+
+// covers reports whether the constraints c match all the requests matched by the constraints other.
+// Like query.Covers, it only relies on the structure of the regular expressions and never reports a false positive.
+func (c *endpointConstraints) covers(other *endpointConstraints) bool {
+	if c.query != nil || other.query != nil {
+		return c.query != nil && other.query != nil && query.Covers(c.query, other.query)
+	}
+	return regexCovers(c.domain, other.domain) &&
+		regexCovers(c.pathInclusion, other.pathInclusion) &&
+		(c.pathExclusion == nil || (other.pathExclusion != nil && regexCovers(other.pathExclusion, c.pathExclusion))) &&
+		regexCovers(c.userAgentInclusion, other.userAgentInclusion)
+}
+
+// regexCovers reports whether the regular expression a matches all the strings matched by the regular expression b,
+// a nil regular expression matching all the strings. Besides identical regular expressions, a regular expression
+// made of a literal, optionally anchored at the start, covers the regular expressions starting with a literal containing it,
+// and a regular expression covers b when one of its top-level alternatives does.
+func regexCovers(a, b *syntax.Regexp) bool {
+	if a == nil {
+		return true
+	}
+	if b == nil {
+		return false
+	}
+	if a.Equal(b) {
+		return true
+	}
+	if alternatives := regexAlternatives(a); len(alternatives) > 1 {
+		for _, alternative := range alternatives {
+			if regexCovers(alternative, b) {
+				return true
+			}
+		}
+		return false
+	}
+
+	anchoredA, literalA, ok := literalRegex(a)
+	if !ok {
+		return false
+	}
+	if literalA == "" {
+		return true
+	}
+	anchoredB, literalB := requiredLiteral(b)
+	if anchoredA {
+		return anchoredB && strings.HasPrefix(literalB, literalA)
+	}
+	return strings.Contains(literalB, literalA)
+}
+
+// literalRegex reports whether a regular expression matches exactly the strings starting with a literal when anchored,
+// or containing it otherwise, and returns this literal
+func literalRegex(re *syntax.Regexp) (anchored bool, literal string, ok bool) {
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+	if len(subs) > 0 && (subs[0].Op == syntax.OpBeginText || subs[0].Op == syntax.OpBeginLine) {
+		anchored = true
+		subs = subs[1:]
+	}
+	var builder strings.Builder
+	for len(subs) > 0 && subs[0].Op == syntax.OpLiteral && subs[0].Flags&syntax.FoldCase == 0 {
+		builder.WriteString(string(subs[0].Rune))
+		subs = subs[1:]
+	}
+	literal = builder.String()
+	// a trailing .* does not restrict the matched strings
+	if len(subs) == 1 && subs[0].Op == syntax.OpStar && (subs[0].Sub[0].Op == syntax.OpAnyChar || subs[0].Sub[0].Op == syntax.OpAnyCharNotNL) {
+		subs = subs[1:]
+	}
+	return anchored, literal, len(subs) == 0
+}
+
+// requiredLiteral returns the literal all the strings matched by a regular expression contain,
+// and whether they start with it
+func requiredLiteral(re *syntax.Regexp) (anchored bool, literal string) {
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+	if len(subs) > 0 && (subs[0].Op == syntax.OpBeginText || subs[0].Op == syntax.OpBeginLine) {
+		anchored = true
+		subs = subs[1:]
+	}
+	var builder strings.Builder
+	for _, sub := range subs {
+		if sub.Op != syntax.OpLiteral || sub.Flags&syntax.FoldCase != 0 {
+			break
+		}
+		builder.WriteString(string(sub.Rune))
+	}
+	return anchored, builder.String()
+}
+
+// regexAlternatives splits a regular expression on its top-level alternation, such as ^/(login|signin),
+// into the regular expressions of each alternative
+func regexAlternatives(re *syntax.Regexp) []*syntax.Regexp {
+	if re == nil {
+		return []*syntax.Regexp{nil}
+	}
+	unwrapped := re
+	for unwrapped.Op == syntax.OpCapture {
+		unwrapped = unwrapped.Sub[0]
+	}
+	if unwrapped.Op == syntax.OpAlternate {
+		return unwrapped.Sub
+	}
+	if unwrapped.Op != syntax.OpConcat {
+		return []*syntax.Regexp{re}
+	}
+
+	position := -1
+	var alternate *syntax.Regexp
+	for i, sub := range unwrapped.Sub {
+		for sub.Op == syntax.OpCapture {
+			sub = sub.Sub[0]
+		}
+		if sub.Op != syntax.OpAlternate {
+			continue
+		}
+		if alternate != nil {
+			return []*syntax.Regexp{re}
+		}
+		position, alternate = i, sub
+	}
+	if alternate == nil {
+		return []*syntax.Regexp{re}
+	}
+
+	alternatives := make([]*syntax.Regexp, 0, len(alternate.Sub))
+	for _, option := range alternate.Sub {
+		subs := append([]*syntax.Regexp{}, unwrapped.Sub...)
+		subs[position] = option
+		alternatives = append(alternatives, &syntax.Regexp{Op: syntax.OpConcat, Flags: unwrapped.Flags, Sub: subs})
+	}
+	return alternatives
+}
+
+// describeRegex describes a regular expression by the literal starting it, or by its syntax
+func describeRegex(re *syntax.Regexp) string {
+	if re == nil {
+		return "all the paths"
+	}
+	if anchored, literal := requiredLiteral(re); literal != "" {
+		if anchored {
+			return "^" + literal
+		}
+		return literal
+	}
+	return re.String()
+}
+
+// endpointShadowing describes an endpoint whose requests are matched, in part or in full, by endpoints evaluated before it
+type endpointShadowing struct {
+	Endpoint    dd.Endpoint
+	ShadowedBy  []dd.Endpoint
+	Unreachable bool
+	// Shadowed lists the description of the alternatives of a partially shadowed endpoint which never receive traffic
+	Shadowed []string
+}
+
+// findShadowedEndpoints returns the endpoints, given in evaluation order, whose requests are matched by endpoints
+// evaluated before them. The endpoints whose regular expressions or query cannot be parsed are ignored.
+func findShadowedEndpoints(ordered []dd.Endpoint) []endpointShadowing {
+	var shadowings []endpointShadowing
+	var previous []dd.Endpoint
+	var previousConstraints []*endpointConstraints
+	for _, endpoint := range ordered {
+		constraints, err := parseEndpointConstraints(endpoint)
+		if err != nil {
+			continue
+		}
+
+		shadowing := endpointShadowing{Endpoint: endpoint}
+		alternatives, descriptions := constraints.alternatives()
+		covered := 0
+		for i, alternative := range alternatives {
+			for j, other := range previousConstraints {
+				if !other.covers(alternative) {
+					continue
+				}
+				covered++
+				shadowing.Shadowed = append(shadowing.Shadowed, descriptions[i])
+				if !endpointListContains(shadowing.ShadowedBy, previous[j]) {
+					shadowing.ShadowedBy = append(shadowing.ShadowedBy, previous[j])
+				}
+				break
+			}
+		}
+		if covered > 0 {
+			shadowing.Unreachable = covered == len(alternatives)
+			shadowings = append(shadowings, shadowing)
+		}
+
+		previous = append(previous, endpoint)
+		previousConstraints = append(previousConstraints, constraints)
+	}
+	return shadowings
+}
+
+// endpointListContains reports whether an endpoint is part of a list, comparing their IDs and names
+func endpointListContains(endpoints []dd.Endpoint, endpoint dd.Endpoint) bool {
+	for _, other := range endpoints {
+		if endpointIDOf(other) == endpointIDOf(endpoint) && other.Name == endpoint.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// endpointIDOf returns the ID of an endpoint, or an empty string when it is not created
+func endpointIDOf(endpoint dd.Endpoint) string {
+	if endpoint.ID == nil {
+		return ""
+	}
+	return *endpoint.ID
+}
+
+// describeEndpoint describes an endpoint by its name in the findings
+func describeEndpoint(endpoint dd.Endpoint) string {
+	if endpoint.Name == "" {
+		return "the planned endpoint"
+	}
+	return fmt.Sprintf("endpoint %q", endpoint.Name)
+}
+
+// describeEndpoints describes a list of endpoints by their names
+func describeEndpoints(endpoints []dd.Endpoint) string {
+	if len(endpoints) == 1 {
+		return describeEndpoint(endpoints[0])
+	}
+	var names []string
+	for _, endpoint := range endpoints {
+		names = append(names, fmt.Sprintf("%q", endpoint.Name))
+	}
+	return fmt.Sprintf("endpoints %s and %s", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+// finding describes the shadowing of an endpoint as a plan finding
+func (s endpointShadowing) finding() planFinding {
+	if s.Unreachable {
+		return planFinding{
+			Summary: "Unreachable endpoint",
+			Detail: fmt.Sprintf("The %s evaluated before %s matches all its requests, so %s never receives traffic.",
+				describeEndpoints(s.ShadowedBy), describeEndpoint(s.Endpoint), describeEndpoint(s.Endpoint)),
+		}
+	}
+	quoted := make([]string, 0, len(s.Shadowed))
+	for _, description := range s.Shadowed {
+		quoted = append(quoted, fmt.Sprintf("%q", description))
+	}
+	return planFinding{
+		Summary: "Partially shadowed endpoint",
+		Detail: fmt.Sprintf("The %s evaluated before %s matches the requests of its alternatives %s, which never reach %s.",
+			describeEndpoints(s.ShadowedBy), describeEndpoint(s.Endpoint), strings.Join(quoted, ", "), describeEndpoint(s.Endpoint)),
+	}
+}
+
+// checkEndpointShadowing places the planned endpoint in the evaluation order of the endpoints of the account,
+// and reports the shadowings involving it: the planned endpoint shadowed by the endpoints evaluated before it,
+// or the endpoints evaluated after it shadowed by the planned endpoint, the endpoints of the account being listed by list.
+// The check only runs when the fields defining which requests the endpoint matches, or its position, change.
+func checkEndpointShadowing(ctx context.Context, data *schema.ResourceDiff, meta interface{}, list func() ([]dd.Endpoint, error)) error {
+	fields := []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"}
+	if data.Id() != "" && !data.HasChanges(append(fields, "position_before")...) {
+		return nil
+	}
+	for _, field := range fields {
+		if !data.NewValueKnown(field) {
+			return nil
+		}
+	}
+
+	planned := dd.Endpoint{
+		Domain:             plannedEndpointField(data, "domain"),
+		PathInclusion:      plannedEndpointField(data, "path_inclusion"),
+		PathExclusion:      plannedEndpointField(data, "path_exclusion"),
+		UserAgentInclusion: plannedEndpointField(data, "user_agent_inclusion"),
+		Query:              plannedEndpointField(data, "query"),
+	}
+	// position_before is unknown when it is not set on creation, the endpoint being placed before the default endpoint
+	if data.NewValueKnown("position_before") {
+		planned.PositionBefore = plannedEndpointField(data, "position_before")
+	}
+	if data.NewValueKnown("name") {
+		planned.Name = data.Get("name").(string)
+	}
+	if id := priorResourceID(data); id != "" {
+		planned.ID = &id
+	}
+
+	if _, err := parseEndpointConstraints(planned); errors.Is(err, errEndpointRegexNotEvaluable) {
+		addPlanWarning(ctx, "Shadowing check skipped", fmt.Sprintf("The shadowing of endpoint %q cannot be checked: %s.", planned.Name, err))
+		return nil
+	}
+
+	endpoints, err := list()
+	if err != nil {
+		log.Printf("[WARN] unable to list endpoints to check the shadowing of endpoint %q: %s", planned.Name, err)
+		return nil
+	}
+	// an endpoint adopted on creation takes the place of the existing endpoint of the same name
+	if planned.ID == nil && planned.Name != "" && data.Get("adopt_existing").(bool) {
+		for _, endpoint := range endpoints {
+			if endpoint.Name == planned.Name {
+				planned.ID = endpoint.ID
+				break
+			}
+		}
+	}
+
+	var findings []planFinding
+	for _, shadowing := range findShadowedEndpoints(placePlannedEndpoint(endpoints, planned)) {
+		if endpointListContains([]dd.Endpoint{shadowing.Endpoint}, planned) || endpointListContains(shadowing.ShadowedBy, planned) {
+			findings = append(findings, shadowing.finding())
+		}
+	}
+	return reportPlanFindings(ctx, meta, findings)
+}
+
+// plannedEndpointField returns the planned value of a field of the endpoint, or nil when it is empty
+func plannedEndpointField(data *schema.ResourceDiff, field string) *string {
+	value := data.Get(field).(string)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// placePlannedEndpoint returns the endpoints in evaluation order, including the planned endpoint:
+// - before the endpoint referenced by its position_before
+// - at its current position when it exists and has no position_before
+// - before the last endpoint, which is the default endpoint, when it is created without position_before
+func placePlannedEndpoint(endpoints []dd.Endpoint, planned dd.Endpoint) []dd.Endpoint {
+	others := make([]dd.Endpoint, 0, len(endpoints)+1)
+	position := -1
+	for i, endpoint := range endpoints {
+		if planned.ID != nil && endpointIDOf(endpoint) == *planned.ID {
+			position = i
+			continue
+		}
+		others = append(others, endpoint)
+	}
+
+	switch {
+	case planned.PositionBefore != nil:
+		return orderEndpoints(append(others, planned))
+	case position >= 0:
+		others = append(others[:position], append([]dd.Endpoint{planned}, others[position:]...)...)
+		return orderEndpoints(others)
+	}
+
+	ordered := orderEndpoints(others)
+	if len(ordered) == 0 {
+		return []dd.Endpoint{planned}
+	}
+	last := len(ordered) - 1
+	return append(ordered[:last], planned, ordered[last])
+}
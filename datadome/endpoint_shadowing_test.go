@@ -0,0 +1,169 @@
+package datadome
+
+import (
+	"context"
+	"regexp/syntax"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegexCovers(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"", "^/api", true},
+		{"^/api", "", false},
+		{"^/api", "^/api", true},
+		{"^/api", "^/api/v1", true},
+		{"^/api/.*", "^/api/v1$", true},
+		{"^/api/v1", "^/api", false},
+		{"^/api", "/api/v1", false},
+		{"api", "^/v1/api/users", true},
+		{"(?i)^/api", "^/api/v1", false},
+		{"^/api$", "^/api/v1", false},
+		{"^/(api|v2)", "^/api/v1", true},
+		{"^/(api|v2)$", "^/api/v1", false},
+		{`example\.com$`, `example\.com$`, true},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.expected, regexCovers(testParseRegex(t, test.a), testParseRegex(t, test.b)), "regexCovers(%q, %q)", test.a, test.b)
+	}
+}
+
+func TestRegexAlternatives(t *testing.T) {
+	describe := func(pattern string) []string {
+		var descriptions []string
+		for _, alternative := range regexAlternatives(testParseRegex(t, pattern)) {
+			descriptions = append(descriptions, describeRegex(alternative))
+		}
+		return descriptions
+	}
+
+	assert.Equal(t, []string{"^/login", "^/signin"}, describe("^/(login|signin)"))
+	assert.Equal(t, []string{"^/login", "^/signin"}, describe("^/login|^/signin"))
+	assert.Equal(t, []string{"^/api"}, describe("^/api"))
+	assert.Equal(t, []string{"all the paths"}, describe(""))
+}
+
+func TestFindShadowedEndpoints(t *testing.T) {
+	endpoint := func(name, domain, pathInclusion string) dd.Endpoint {
+		id := name + "-id"
+		endpoint := dd.Endpoint{ID: &id, Name: name}
+		if domain != "" {
+			endpoint.Domain = &domain
+		}
+		if pathInclusion != "" {
+			endpoint.PathInclusion = &pathInclusion
+		}
+		return endpoint
+	}
+	api := endpoint("api", `example\.com$`, "^/api")
+	apiV1 := endpoint("api-v1", `example\.com$`, "^/api/v1")
+	otherDomain := endpoint("other-domain", `example\.org$`, "^/api/v1")
+	auth := endpoint("auth", `example\.com$`, "^/(api/login|signin)")
+	web := endpoint("web", "", "")
+
+	shadowings := findShadowedEndpoints([]dd.Endpoint{api, apiV1, otherDomain, auth, web})
+	findings := make([]planFinding, 0, len(shadowings))
+	for _, shadowing := range shadowings {
+		findings = append(findings, shadowing.finding())
+	}
+	assert.Equal(t, []planFinding{
+		{
+			Summary: "Unreachable endpoint",
+			Detail:  `The endpoint "api" evaluated before endpoint "api-v1" matches all its requests, so endpoint "api-v1" never receives traffic.`,
+		},
+		{
+			Summary: "Partially shadowed endpoint",
+			Detail:  `The endpoint "api" evaluated before endpoint "auth" matches the requests of its alternatives "^/api/login", which never reach endpoint "auth".`,
+		},
+	}, findings)
+
+	query := "url:/api/*"
+	queryEndpoint := dd.Endpoint{Name: "query", Query: &query}
+	otherQuery := "url:/api/v1/users OR url:/signin"
+	otherQueryEndpoint := dd.Endpoint{Name: "other-query", Query: &otherQuery}
+	shadowings = findShadowedEndpoints([]dd.Endpoint{queryEndpoint, otherQueryEndpoint, api})
+	if assert.Len(t, shadowings, 1) {
+		assert.False(t, shadowings[0].Unreachable)
+		assert.Equal(t, []string{"url:/api/v1/users"}, shadowings[0].Shadowed)
+	}
+}
+
+func TestPlaceEndpoint(t *testing.T) {
+	names := func(endpoints []dd.Endpoint) []string {
+		var result []string
+		for _, endpoint := range endpoints {
+			result = append(result, endpoint.Name)
+		}
+		return result
+	}
+	endpoints := []dd.Endpoint{testEndpoint("a", ""), testEndpoint("b", ""), testEndpoint("default", "")}
+
+	assert.Equal(t, []string{"a", "b", "new", "default"}, names(placePlannedEndpoint(endpoints, dd.Endpoint{Name: "new"})))
+
+	positionBefore := "a"
+	assert.Equal(t, []string{"new", "a", "b", "default"}, names(placePlannedEndpoint(endpoints, dd.Endpoint{Name: "new", PositionBefore: &positionBefore})))
+
+	id := "b"
+	assert.Equal(t, []string{"a", "b-renamed", "default"}, names(placePlannedEndpoint(endpoints, dd.Endpoint{ID: &id, Name: "b-renamed"})))
+}
+
+func testParseRegex(t *testing.T, pattern string) *syntax.Regexp {
+	if pattern == "" {
+		return nil
+	}
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return re
+}
+
+func TestPlanWarnings_shadowedEndpoint(t *testing.T) {
+	mockClient := dd.NewMockClientEndpoint()
+	apiID, defaultID := "00000000-0000-4000-8000-000000000001", "00000000-0000-4000-8000-000000000002"
+	for _, endpoint := range []dd.Endpoint{
+		{ID: &apiID, Name: "api", Domain: testStringPointer(`example\.com$`), PathInclusion: testStringPointer("^/api")},
+		{ID: &defaultID, Name: "default", Domain: testStringPointer(".*")},
+	} {
+		if _, err := mockClient.Create(context.Background(), endpoint); err != nil {
+			t.Fatal(err)
+		}
+	}
+	endpoint := map[string]interface{}{
+		"name":           "api-v1",
+		"source":         "Web Browser",
+		"traffic_usage":  "General",
+		"domain":         `example\.com$`,
+		"path_inclusion": "^/api/v1",
+	}
+
+	resp := testPlanResource(t, &ProviderConfig{ClientEndpoint: mockClient}, "datadome_endpoint", endpoint)
+
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, resp.Diagnostics[0].Severity)
+		assert.Equal(t, "Unreachable endpoint", resp.Diagnostics[0].Summary)
+		assert.Equal(t, `The endpoint "api" evaluated before endpoint "api-v1" matches all its requests, so endpoint "api-v1" never receives traffic.`, resp.Diagnostics[0].Detail)
+	}
+
+	resp = testPlanResource(t, &ProviderConfig{ClientEndpoint: mockClient, StrictPlanChecks: true}, "datadome_endpoint", endpoint)
+
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityError, resp.Diagnostics[0].Severity)
+		assert.Contains(t, resp.Diagnostics[0].Summary, "Unreachable endpoint: ")
+	}
+
+	endpoint["position_before"] = apiID
+	resp = testPlanResource(t, &ProviderConfig{ClientEndpoint: mockClient}, "datadome_endpoint", endpoint)
+
+	assert.Empty(t, resp.Diagnostics)
+}
+
+func testStringPointer(value string) *string {
+	return &value
+}
@@ -0,0 +1,151 @@
+package datadome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// endpointTestCase is a sample request, with whether the endpoint is expected to match it
+type endpointTestCase struct {
+	URL         string
+	UserAgent   string
+	ExpectMatch bool
+}
+
+// request returns the request of the test case, whose path defaults to "/"
+func (t endpointTestCase) request() (endpointRequest, error) {
+	parsed, err := url.Parse(t.URL)
+	if err != nil {
+		return endpointRequest{}, err
+	}
+	if parsed.Hostname() == "" {
+		return endpointRequest{}, fmt.Errorf("%q has no host", t.URL)
+	}
+	path := parsed.Path
+	if path == "" {
+		path = "/"
+	}
+	return endpointRequest{Host: parsed.Hostname(), Path: path, UserAgent: t.UserAgent}, nil
+}
+
+// String describes the test case in the error messages
+func (t endpointTestCase) String() string {
+	if t.UserAgent == "" {
+		return t.URL
+	}
+	return fmt.Sprintf("%s with the user agent %q", t.URL, t.UserAgent)
+}
+
+// endpointTestCaseSchema is the schema of the test_case block of the endpoints
+var endpointTestCaseSchema = &schema.Resource{
+	Schema: map[string]*schema.Schema{
+		"url": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+		},
+		"user_agent": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"expect_match": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+	},
+}
+
+// failingEndpointTestCases evaluates the test cases against the regular expressions of an endpoint,
+// and returns the description of each failing test case.
+func failingEndpointTestCases(endpoint dd.Endpoint, testCases []endpointTestCase) ([]string, error) {
+	matcher, err := newEndpointMatcher(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []string
+	for _, testCase := range testCases {
+		request, err := testCase.request()
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: invalid url: %s", testCase, err))
+			continue
+		}
+		if matched := matcher.Matches(request); matched != testCase.ExpectMatch {
+			if testCase.ExpectMatch {
+				failures = append(failures, fmt.Sprintf("%s: expected to match the endpoint, but it does not", testCase))
+			} else {
+				failures = append(failures, fmt.Sprintf("%s: expected not to match the endpoint, but it does", testCase))
+			}
+		}
+	}
+	return failures, nil
+}
+
+// checkEndpointTestCases raises an error listing the test cases of the endpoint which fail against its planned regular expressions.
+// The check is skipped while a regular expression or a test case is unknown,
+// and with a warning when a regular expression uses PCRE constructs which the provider cannot evaluate.
+func checkEndpointTestCases(ctx context.Context, data *schema.ResourceDiff) error {
+	if !data.NewValueKnown("test_case") {
+		return nil
+	}
+	rawTestCases := data.Get("test_case").([]interface{})
+	if len(rawTestCases) == 0 {
+		return nil
+	}
+
+	endpoint := dd.Endpoint{Name: data.Get("name").(string)}
+	for _, field := range []struct {
+		name   string
+		target **string
+	}{
+		{"domain", &endpoint.Domain},
+		{"path_inclusion", &endpoint.PathInclusion},
+		{"path_exclusion", &endpoint.PathExclusion},
+		{"user_agent_inclusion", &endpoint.UserAgentInclusion},
+		{"query", &endpoint.Query},
+	} {
+		if !data.NewValueKnown(field.name) {
+			return nil
+		}
+		if value := data.Get(field.name).(string); value != "" {
+			*field.target = &value
+		}
+	}
+	if endpoint.Query != nil {
+		return fmt.Errorf("test_case: the test cases cannot be evaluated on an endpoint defined by a query")
+	}
+
+	testCases := make([]endpointTestCase, 0, len(rawTestCases))
+	for _, v := range rawTestCases {
+		block, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		testCases = append(testCases, endpointTestCase{
+			URL:         block["url"].(string),
+			UserAgent:   block["user_agent"].(string),
+			ExpectMatch: block["expect_match"].(bool),
+		})
+	}
+
+	failures, err := failingEndpointTestCases(endpoint, testCases)
+	if errors.Is(err, errEndpointRegexNotEvaluable) {
+		addPlanWarning(ctx, "Test cases skipped", fmt.Sprintf("The test cases cannot be evaluated: %s.", err))
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("test_case: %w", err)
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("test_case: %d of %d test cases failed:\n- %s", len(failures), len(testCases), strings.Join(failures, "\n- "))
+	}
+	return nil
+}
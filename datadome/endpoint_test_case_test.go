@@ -0,0 +1,66 @@
+package datadome
+
+import (
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEndpointTestCaseRequest(t *testing.T) {
+	request, err := endpointTestCase{URL: "https://www.example.com:8443", UserAgent: "MyApp/1.0"}.request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, endpointRequest{Host: "www.example.com", Path: "/", UserAgent: "MyApp/1.0"}, request)
+	}
+
+	request, err = endpointTestCase{URL: "https://www.example.com/login?next=/home"}.request()
+	if assert.NoError(t, err) {
+		assert.Equal(t, endpointRequest{Host: "www.example.com", Path: "/login"}, request)
+	}
+
+	_, err = endpointTestCase{URL: "/login"}.request()
+	assert.EqualError(t, err, `"/login" has no host`)
+}
+
+func TestFailingEndpointTestCases(t *testing.T) {
+	domain, pathInclusion, userAgent := `example\.com$`, `^/login`, `MyApp/`
+	endpoint := dd.Endpoint{Name: "login", Domain: &domain, PathInclusion: &pathInclusion, UserAgentInclusion: &userAgent}
+
+	failures, err := failingEndpointTestCases(endpoint, []endpointTestCase{
+		{URL: "https://www.example.com/login", UserAgent: "MyApp/1.0", ExpectMatch: true},
+		{URL: "https://www.example.com/home", UserAgent: "MyApp/1.0", ExpectMatch: false},
+		{URL: "https://www.example.org/login", UserAgent: "MyApp/1.0", ExpectMatch: true},
+		{URL: "https://www.example.com/login", UserAgent: "MyApp/1.0", ExpectMatch: false},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			`https://www.example.org/login with the user agent "MyApp/1.0": expected to match the endpoint, but it does not`,
+			`https://www.example.com/login with the user agent "MyApp/1.0": expected not to match the endpoint, but it does`,
+		}, failures)
+	}
+
+	invalid := "("
+	_, err = failingEndpointTestCases(dd.Endpoint{Name: "invalid", Domain: &invalid}, []endpointTestCase{{URL: "https://example.com", ExpectMatch: true}})
+	assert.EqualError(t, err, `endpoint "invalid": domain: missing closing ) in "("`)
+}
+
+func TestPlanWarnings_endpointTestCasesSkipped(t *testing.T) {
+	endpoint := map[string]interface{}{
+		"name":           "api",
+		"source":         "Web Browser",
+		"traffic_usage":  "General",
+		"domain":         `example\.com$`,
+		"path_inclusion": `^/(?!admin)`,
+		"test_case":      []interface{}{map[string]interface{}{"url": "https://example.com/admin", "expect_match": true}},
+	}
+
+	resp := testPlanResource(t, &ProviderConfig{ClientEndpoint: dd.NewMockClientEndpoint()}, "datadome_endpoint", endpoint)
+
+	if assert.Len(t, resp.Diagnostics, 2) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, resp.Diagnostics[0].Severity)
+		assert.Equal(t, "Test cases skipped", resp.Diagnostics[0].Summary)
+		assert.Equal(t, `The test cases cannot be evaluated: endpoint "api": path_inclusion: negative lookaheads ("(?!" at offset 2) cannot be evaluated by the provider.`, resp.Diagnostics[0].Detail)
+		assert.Equal(t, "Shadowing check skipped", resp.Diagnostics[1].Summary)
+	}
+}
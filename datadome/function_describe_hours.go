@@ -0,0 +1,60 @@
+package datadome
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// describeHoursFunction summarizes the hour slots of a time box in a readable way
+type describeHoursFunction struct{}
+
+var _ function.Function = &describeHoursFunction{}
+
+func newDescribeHoursFunction() function.Function {
+	return &describeHoursFunction{}
+}
+
+func (f *describeHoursFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "describe_hours"
+}
+
+func (f *describeHoursFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Describe the authorized hours of the week of a time box",
+		MarkdownDescription: "Summarizes hour slots of the week in UTC, such as the ones of `authorized_hours_of_the_week`, " +
+			"grouping the days sharing the same time ranges, for instance `Mon-Fri 07:00-19:00; Sat 09:00-11:00`. " +
+			"The result can be given back to `hours_of_week` with the `UTC` time zone.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:                "hours",
+				ElementType:         types.Int64Type,
+				MarkdownDescription: "Hour slots of the week, from `0` (Monday 00:00 UTC) to `167` (Sunday 23:00 UTC).",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *describeHoursFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var hours []int64
+
+	resp.Error = req.Arguments.Get(ctx, &hours)
+	if resp.Error != nil {
+		return
+	}
+
+	slots := make([]int, len(hours))
+	for i, hour := range hours {
+		slots[i] = int(hour)
+	}
+
+	description, err := describeHoursOfWeek(slots)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, description)
+}
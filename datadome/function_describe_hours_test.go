@@ -0,0 +1,42 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribeHoursFunction(t *testing.T) {
+	hours, _ := types.ListValueFrom(context.Background(), types.Int64Type, []int64{33, 34, 9, 10, 130})
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{hours}),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+
+	newDescribeHoursFunction().Run(context.Background(), req, resp)
+
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, types.StringValue("Mon-Tue 09:00-11:00; Sat 10:00-11:00"), resp.Result.Value())
+}
+
+func TestDescribeHoursFunction_Invalid(t *testing.T) {
+	hours, _ := types.ListValueFrom(context.Background(), types.Int64Type, []int64{9, 168})
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{hours}),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+
+	newDescribeHoursFunction().Run(context.Background(), req, resp)
+
+	if assert.NotNil(t, resp.Error) {
+		assert.Contains(t, resp.Error.Text, "invalid hour 168")
+	}
+}
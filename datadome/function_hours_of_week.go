@@ -0,0 +1,109 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// hoursOfWeekFunction converts a textual description of authorized hours into the hour slots of a time box
+type hoursOfWeekFunction struct{}
+
+var _ function.Function = &hoursOfWeekFunction{}
+
+func newHoursOfWeekFunction() function.Function {
+	return &hoursOfWeekFunction{}
+}
+
+func (f *hoursOfWeekFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "hours_of_week"
+}
+
+func (f *hoursOfWeekFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build the authorized hours of the week of a time box",
+		MarkdownDescription: "Converts authorized hours such as `Mon-Fri 08:00-20:00` in the given time zone into the hour slots of the week in UTC " +
+			"expected by `authorized_hours_of_the_week`, from `0` (Monday 00:00 UTC) to `167` (Sunday 23:00 UTC), " +
+			"using the UTC offsets of the time zone during the week of the given date, or its standard time when no date is given.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name: "hours",
+				MarkdownDescription: "Entries separated by `;`, each made of days and time ranges on the hour separated by `,`, " +
+					"for instance `Mon-Fri 08:00-12:00,14:00-18:00; Sat 10:00-12:00`.",
+			},
+			function.StringParameter{
+				Name:                "timezone",
+				MarkdownDescription: "IANA time zone of the time ranges, for instance `Europe/Paris` or `UTC`.",
+			},
+		},
+		VariadicParameter: function.StringParameter{
+			Name: "date",
+			MarkdownDescription: "Optional date with the `YYYY-MM-DD` format whose week gives the UTC offsets of the time zone, for instance `2026-07-06`. " +
+				"When omitted, the standard time of the time zone is used, without daylight saving time. " +
+				"The same arguments always return the same hours, whatever the current date.",
+		},
+		Return: function.ListReturn{
+			ElementType: types.Int64Type,
+		},
+	}
+}
+
+func (f *hoursOfWeekFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var description, timezone string
+	var dates []string
+
+	resp.Error = req.Arguments.Get(ctx, &description, &timezone, &dates)
+	if resp.Error != nil {
+		return
+	}
+	if len(dates) > 1 {
+		resp.Error = function.NewArgumentFuncError(3, fmt.Sprintf("expected at most one date, got %d", len(dates)))
+		return
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid time zone %q: %s", timezone, err))
+		return
+	}
+
+	reference := standardTimeReference(location)
+	if len(dates) == 1 {
+		reference, err = time.ParseInLocation(time.DateOnly, dates[0], location)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(2, fmt.Sprintf("invalid date %q, expected the YYYY-MM-DD format", dates[0]))
+			return
+		}
+	}
+
+	schedules, err := parseHoursOfWeek(description, timezone)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	hours, err := expandWeeklySchedules(schedules, reference)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	result := make([]int64, len(hours))
+	for i, hour := range hours {
+		result[i] = int64(hour)
+	}
+	resp.Error = resp.Result.Set(ctx, result)
+}
+
+// standardTimeReference returns a fixed date of a week where the time zone observes its standard time,
+// the first week of January or of July depending on the hemisphere, so the result does not depend on the current date
+func standardTimeReference(location *time.Location) time.Time {
+	reference := time.Date(2026, time.January, 5, 0, 0, 0, 0, location)
+	if reference.IsDST() {
+		return time.Date(2026, time.July, 6, 0, 0, 0, 0, location)
+	}
+	return reference
+}
@@ -0,0 +1,142 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// testHoursOfWeekArguments returns the arguments of hours_of_week, the optional dates being passed as the variadic parameter
+func testHoursOfWeekArguments(hours, timezone string, dates ...string) function.ArgumentsData {
+	elementTypes := make([]attr.Type, len(dates))
+	elements := make([]attr.Value, len(dates))
+	for i, date := range dates {
+		elementTypes[i] = types.StringType
+		elements[i] = types.StringValue(date)
+	}
+	return function.NewArgumentsData([]attr.Value{
+		types.StringValue(hours),
+		types.StringValue(timezone),
+		types.TupleValueMust(elementTypes, elements),
+	})
+}
+
+func TestHoursOfWeekFunction(t *testing.T) {
+	req := function.RunRequest{
+		Arguments: testHoursOfWeekArguments("Mon,Wed 09:00-11:00", "UTC", "2026-01-05"),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.ListUnknown(types.Int64Type)),
+	}
+
+	newHoursOfWeekFunction().Run(context.Background(), req, resp)
+
+	assert.Nil(t, resp.Error)
+	expected, _ := types.ListValueFrom(context.Background(), types.Int64Type, []int64{9, 10, 57, 58})
+	assert.Equal(t, expected, resp.Result.Value())
+}
+
+func TestHoursOfWeekFunction_Timezone(t *testing.T) {
+	tests := []struct {
+		name     string
+		timezone string
+		dates    []string
+		expected []int64
+	}{
+		{name: "winter", timezone: "Europe/Paris", dates: []string{"2026-01-07"}, expected: []int64{7, 8}},
+		{name: "summer", timezone: "Europe/Paris", dates: []string{"2026-07-08"}, expected: []int64{6, 7}},
+		{name: "standard time", timezone: "Europe/Paris", expected: []int64{7, 8}},
+		{name: "standard time in the southern hemisphere", timezone: "Australia/Sydney", expected: []int64{166, 167}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: testHoursOfWeekArguments("Mon 08:00-10:00", test.timezone, test.dates...),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(types.Int64Type)),
+			}
+
+			newHoursOfWeekFunction().Run(context.Background(), req, resp)
+
+			assert.Nil(t, resp.Error)
+			expected, _ := types.ListValueFrom(context.Background(), types.Int64Type, test.expected)
+			assert.Equal(t, expected, resp.Result.Value())
+		})
+	}
+}
+
+func TestHoursOfWeekFunction_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		hours    string
+		timezone string
+		dates    []string
+		argument int64
+		expected string
+	}{
+		{
+			name:     "unknown day",
+			hours:    "Monday 08:00-10:00",
+			timezone: "UTC",
+			dates:    []string{"2026-01-05"},
+			argument: 0,
+			expected: `invalid day of the week "Monday"`,
+		},
+		{
+			name:     "missing time range",
+			hours:    "Mon-Fri",
+			timezone: "UTC",
+			dates:    []string{"2026-01-05"},
+			argument: 0,
+			expected: `expected days followed by time ranges`,
+		},
+		{
+			name:     "unknown time zone",
+			hours:    "Mon 08:00-10:00",
+			timezone: "Mars/Olympus_Mons",
+			dates:    []string{"2026-01-05"},
+			argument: 1,
+			expected: `invalid time zone "Mars/Olympus_Mons"`,
+		},
+		{
+			name:     "invalid date",
+			hours:    "Mon 08:00-10:00",
+			timezone: "UTC",
+			dates:    []string{"05/01/2026"},
+			argument: 2,
+			expected: `invalid date "05/01/2026"`,
+		},
+		{
+			name:     "several dates",
+			hours:    "Mon 08:00-10:00",
+			timezone: "UTC",
+			dates:    []string{"2026-01-05", "2026-07-06"},
+			argument: 3,
+			expected: `expected at most one date, got 2`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := function.RunRequest{
+				Arguments: testHoursOfWeekArguments(test.hours, test.timezone, test.dates...),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.ListUnknown(types.Int64Type)),
+			}
+
+			newHoursOfWeekFunction().Run(context.Background(), req, resp)
+
+			if assert.NotNil(t, resp.Error) {
+				assert.Equal(t, &test.argument, resp.Error.FunctionArgument)
+				assert.Contains(t, resp.Error.Text, test.expected)
+			}
+		})
+	}
+}
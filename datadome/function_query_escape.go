@@ -0,0 +1,52 @@
+package datadome
+
+import (
+	"context"
+
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// queryEscapeFunction escapes a value to be matched literally in a query
+type queryEscapeFunction struct{}
+
+var _ function.Function = &queryEscapeFunction{}
+
+func newQueryEscapeFunction() function.Function {
+	return &queryEscapeFunction{}
+}
+
+func (f *queryEscapeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "query_escape"
+}
+
+func (f *queryEscapeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Escape a value of a query",
+		MarkdownDescription: "Escapes the spaces and the special characters `( ) \" : \\ *` of a value with backslashes, " +
+			"so it is matched literally by a query term, including between wildcards such as `*${value}*`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "value",
+				MarkdownDescription: "Value to escape.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *queryEscapeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var value string
+
+	resp.Error = req.Arguments.Get(ctx, &value)
+	if resp.Error != nil {
+		return
+	}
+
+	if value == "" {
+		resp.Error = function.NewArgumentFuncError(0, "value must not be empty")
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, query.Escape(value))
+}
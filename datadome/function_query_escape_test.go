@@ -0,0 +1,40 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryEscapeFunction(t *testing.T) {
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(`python requests (v2): *`)}),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+
+	newQueryEscapeFunction().Run(context.Background(), req, resp)
+
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, types.StringValue(`python\ requests\ \(v2\)\:\ \*`), resp.Result.Value())
+}
+
+func TestQueryEscapeFunction_Empty(t *testing.T) {
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("")}),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+
+	newQueryEscapeFunction().Run(context.Background(), req, resp)
+
+	if assert.NotNil(t, resp.Error) {
+		assert.Contains(t, resp.Error.Text, "value must not be empty")
+	}
+}
@@ -0,0 +1,74 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// queryGroupFunction combines queries with the AND or the OR operator
+type queryGroupFunction struct {
+	operator string
+	combine  func(operands ...query.Expr) query.Expr
+}
+
+var _ function.Function = &queryGroupFunction{}
+
+func newQueryAndFunction() function.Function {
+	return &queryGroupFunction{operator: "AND", combine: query.AllOf}
+}
+
+func newQueryOrFunction() function.Function {
+	return &queryGroupFunction{operator: "OR", combine: query.AnyOf}
+}
+
+func (f *queryGroupFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "query_" + strings.ToLower(f.operator)
+}
+
+func (f *queryGroupFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	matching := "all the queries match"
+	if f.operator == "OR" {
+		matching = "any of the queries matches"
+	}
+	resp.Definition = function.Definition{
+		Summary: fmt.Sprintf("Combine queries with the %s operator", f.operator),
+		MarkdownDescription: fmt.Sprintf("Combines queries into a canonical query matching when %s, "+
+			"enclosing the nested groups in parentheses so the precedence of the operators does not matter.", matching),
+		VariadicParameter: function.StringParameter{
+			Name:                "queries",
+			MarkdownDescription: "Queries to combine, at least one.",
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *queryGroupFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var queries []string
+
+	resp.Error = req.Arguments.Get(ctx, &queries)
+	if resp.Error != nil {
+		return
+	}
+
+	if len(queries) == 0 {
+		resp.Error = function.NewArgumentFuncError(0, "at least one query is required")
+		return
+	}
+
+	operands := make([]query.Expr, len(queries))
+	for i, q := range queries {
+		expr, err := query.Parse(q)
+		if err != nil {
+			// the variadic parameter is a single argument, so the invalid query is identified in the message
+			resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("query %d: %s", i, err))
+			return
+		}
+		operands[i] = expr
+	}
+
+	resp.Error = resp.Result.Set(ctx, f.combine(operands...).String())
+}
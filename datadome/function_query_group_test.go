@@ -0,0 +1,54 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func runQueryGroupFunction(f function.Function, queries ...string) *function.RunResponse {
+	elementTypes := make([]attr.Type, len(queries))
+	elements := make([]attr.Value, len(queries))
+	for i, q := range queries {
+		elementTypes[i] = types.StringType
+		elements[i] = types.StringValue(q)
+	}
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.TupleValueMust(elementTypes, elements)}),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+	f.Run(context.Background(), req, resp)
+	return resp
+}
+
+func TestQueryAndFunction(t *testing.T) {
+	resp := runQueryGroupFunction(newQueryAndFunction(), "ip: 10.0.0.1", "url:/login OR url:/signup", "asn:(1234 OR 5678) AND countrycode:FR")
+
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, types.StringValue("ip:10.0.0.1 AND (url:/login OR url:/signup) AND asn:(1234 OR 5678) AND countrycode:FR"), resp.Result.Value())
+}
+
+func TestQueryOrFunction(t *testing.T) {
+	resp := runQueryGroupFunction(newQueryOrFunction(), "ip:10.0.0.1", "url:/login AND NOT countrycode:FR")
+
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, types.StringValue("ip:10.0.0.1 OR (url:/login AND NOT countrycode:FR)"), resp.Result.Value())
+}
+
+func TestQueryGroupFunction_Invalid(t *testing.T) {
+	resp := runQueryGroupFunction(newQueryAndFunction(), "ip:10.0.0.1", "url:(/login")
+	if assert.NotNil(t, resp.Error) {
+		assert.Contains(t, resp.Error.Text, `query 1: invalid query "url:(/login"`)
+	}
+
+	resp = runQueryGroupFunction(newQueryOrFunction())
+	if assert.NotNil(t, resp.Error) {
+		assert.Contains(t, resp.Error.Text, "at least one query is required")
+	}
+}
@@ -0,0 +1,75 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// queryInFunction builds a query term matching any of the given values of a field
+type queryInFunction struct{}
+
+var _ function.Function = &queryInFunction{}
+
+func newQueryInFunction() function.Function {
+	return &queryInFunction{}
+}
+
+func (f *queryInFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "query_in"
+}
+
+func (f *queryInFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Build a query term matching any of the values of a field",
+		MarkdownDescription: "Builds a query term matching when the field is equal to any of the values, " +
+			"such as `ip:(192.168.0.0/24 OR 10.0.0.1)`. The values are matched literally and quoted when needed.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "field",
+				MarkdownDescription: "Name of the field, such as `ip`, `asn`, `url` or `headers.user-agent`.",
+			},
+			function.ListParameter{
+				Name:                "values",
+				ElementType:         types.StringType,
+				MarkdownDescription: "Values of the field, at least one.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *queryInFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var field string
+	var values []string
+
+	resp.Error = req.Arguments.Get(ctx, &field, &values)
+	if resp.Error != nil {
+		return
+	}
+
+	if !queryFieldRegexp.MatchString(field) {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid field %q, expected a field name such as \"ip\" or \"countrycode\"", field))
+		return
+	}
+	if len(values) == 0 {
+		resp.Error = function.NewArgumentFuncError(1, "at least one value is required")
+		return
+	}
+	for i, value := range values {
+		if value == "" {
+			resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("value %d must not be empty", i))
+			return
+		}
+	}
+
+	result, err := query.Build(query.Field(field).In(values...))
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+	resp.Error = resp.Result.Set(ctx, result)
+}
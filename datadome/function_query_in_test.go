@@ -0,0 +1,56 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryInFunction(t *testing.T) {
+	values, _ := types.ListValueFrom(context.Background(), types.StringType, []string{"192.168.0.0/24", "10.0.0.1", "python requests"})
+	req := function.RunRequest{
+		Arguments: function.NewArgumentsData([]attr.Value{types.StringValue("ip"), values}),
+	}
+	resp := &function.RunResponse{
+		Result: function.NewResultData(types.StringUnknown()),
+	}
+
+	newQueryInFunction().Run(context.Background(), req, resp)
+
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, types.StringValue(`ip:(192.168.0.0/24 OR 10.0.0.1 OR "python requests")`), resp.Result.Value())
+}
+
+func TestQueryInFunction_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		field    string
+		values   []string
+		expected string
+	}{
+		"invalid field": {field: "ip address", values: []string{"10.0.0.1"}, expected: `invalid field "ip address"`},
+		"no values":     {field: "ip", values: []string{}, expected: "at least one value is required"},
+		"empty value":   {field: "ip", values: []string{"10.0.0.1", ""}, expected: "value 1 must not be empty"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			values, _ := types.ListValueFrom(context.Background(), types.StringType, test.values)
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{types.StringValue(test.field), values}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+
+			newQueryInFunction().Run(context.Background(), req, resp)
+
+			if assert.NotNil(t, resp.Error) {
+				assert.Contains(t, resp.Error.Text, test.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package datadome
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	// Embed the IANA time zone database so that schedules do not depend on the host configuration
+	_ "time/tzdata"
+)
+
+// weekDays lists the abbreviated days of the week in the order of the hour slots, which start on Monday
+var weekDays = []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+// hoursInWeek is the number of hour slots in a week, from 0 (Monday 00:00 UTC) to 167 (Sunday 23:00 UTC)
+const hoursInWeek = 7 * 24
+
+// weeklySchedule describes authorized hours repeated on some days of the week, in a given time zone
+type weeklySchedule struct {
+	Days     []string
+	Start    string
+	End      string
+	Timezone string
+}
+
+// parseWeekDay returns the index of the given abbreviated day of the week, starting from 0 for Monday
+func parseWeekDay(value string) (int, error) {
+	for i, day := range weekDays {
+		if strings.EqualFold(day, value) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day of the week %q, expected one of %s", value, strings.Join(weekDays, ", "))
+}
+
+// parseHourOfDay parses a time on the hour with the "HH:MM" format.
+// "24:00" is only accepted when endOfDay is true.
+func parseHourOfDay(value string, endOfDay bool) (int, error) {
+	hours, minutes, ok := strings.Cut(value, ":")
+	if !ok || len(hours) != 2 || len(minutes) != 2 {
+		return 0, fmt.Errorf("invalid time %q, expected the HH:MM format", value)
+	}
+
+	hour, err := strconv.Atoi(hours)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected the HH:MM format", value)
+	}
+	if minutes != "00" {
+		return 0, fmt.Errorf("invalid time %q, authorized hours must start and end on the hour", value)
+	}
+
+	maxHour := 23
+	if endOfDay {
+		maxHour = 24
+	}
+	if hour < 0 || hour > maxHour {
+		return 0, fmt.Errorf("invalid time %q, expected an hour between 00:00 and %02d:00", value, maxHour)
+	}
+
+	return hour, nil
+}
+
+// hours converts the schedule into hour slots of the week in UTC, for the week containing reference.
+// The time zone offsets of that week are used, so a schedule defined in a time zone observing
+// daylight saving time follows the local time when it is expanded again after a change of offset.
+// An end time before the start time spans midnight.
+func (s weeklySchedule) hours(reference time.Time) ([]int, error) {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", s.Timezone, err)
+	}
+
+	start, err := parseHourOfDay(s.Start, false)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseHourOfDay(s.End, true)
+	if err != nil {
+		return nil, err
+	}
+	if start == end {
+		return nil, fmt.Errorf("invalid schedule %s-%s, the end time must differ from the start time", s.Start, s.End)
+	}
+	if end < start {
+		end += 24
+	}
+
+	localReference := reference.In(location)
+	monday := time.Date(localReference.Year(), localReference.Month(), localReference.Day()-(int(localReference.Weekday())+6)%7, 0, 0, 0, 0, location)
+
+	var hours []int
+	for _, day := range s.Days {
+		dayIndex, err := parseWeekDay(day)
+		if err != nil {
+			return nil, err
+		}
+		for hour := start; hour < end; hour++ {
+			utc := time.Date(monday.Year(), monday.Month(), monday.Day()+dayIndex, hour, 0, 0, 0, location).UTC()
+			hours = append(hours, ((int(utc.Weekday())+6)%7)*24+utc.Hour())
+		}
+	}
+
+	return normalizeHoursOfWeek(hours), nil
+}
+
+// expandWeeklySchedules converts the schedules into sorted and unique hour slots of the week in UTC
+func expandWeeklySchedules(schedules []weeklySchedule, reference time.Time) ([]int, error) {
+	var hours []int
+	for _, schedule := range schedules {
+		scheduleHours, err := schedule.hours(reference)
+		if err != nil {
+			return nil, err
+		}
+		hours = append(hours, scheduleHours...)
+	}
+	return normalizeHoursOfWeek(hours), nil
+}
+
+// parseHoursOfWeek parses a textual description of authorized hours into weekly schedules in the given time zone.
+// The description is made of entries separated by ";", each entry listing days and time ranges,
+// for instance "Mon-Fri 08:00-12:00,14:00-18:00; Sat 10:00-12:00".
+// Days are separated by "," and a range of days such as "Fri-Mon" may wrap over the end of the week.
+func parseHoursOfWeek(description, timezone string) ([]weeklySchedule, error) {
+	var schedules []weeklySchedule
+	for _, entry := range strings.Split(description, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		separator := strings.IndexAny(entry, "0123456789")
+		if separator <= 0 {
+			return nil, fmt.Errorf("invalid hours %q, expected days followed by time ranges such as \"Mon-Fri 08:00-20:00\"", entry)
+		}
+
+		days, err := parseWeekDays(entry[:separator])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, timeRange := range strings.Split(entry[separator:], ",") {
+			start, end, ok := strings.Cut(strings.TrimSpace(timeRange), "-")
+			if !ok {
+				return nil, fmt.Errorf("invalid time range %q, expected the HH:MM-HH:MM format", strings.TrimSpace(timeRange))
+			}
+			schedules = append(schedules, weeklySchedule{
+				Days:     days,
+				Start:    strings.TrimSpace(start),
+				End:      strings.TrimSpace(end),
+				Timezone: timezone,
+			})
+		}
+	}
+
+	if len(schedules) == 0 {
+		return nil, fmt.Errorf("no hours found in %q", description)
+	}
+	return schedules, nil
+}
+
+// parseWeekDays parses days and ranges of days separated by ",", such as "Mon-Wed,Fri"
+func parseWeekDays(value string) ([]string, error) {
+	var days []string
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+
+		first, err := parseWeekDay(strings.TrimSpace(from))
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseWeekDay(strings.TrimSpace(to)); err != nil {
+				return nil, err
+			}
+		}
+
+		for i := first; ; i = (i + 1) % len(weekDays) {
+			if !slices.Contains(days, weekDays[i]) {
+				days = append(days, weekDays[i])
+			}
+			if i == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+// describeHoursOfWeek summarizes hour slots of the week in UTC with the format read by parseHoursOfWeek.
+// Days sharing the same time ranges are grouped together, for instance "Mon-Fri 08:00-20:00; Sat 10:00-12:00".
+func describeHoursOfWeek(hours []int) (string, error) {
+	var authorized [hoursInWeek]bool
+	for _, hour := range hours {
+		if hour < 0 || hour >= hoursInWeek {
+			return "", fmt.Errorf("invalid hour %d, expected a value between 0 and %d", hour, hoursInWeek-1)
+		}
+		authorized[hour] = true
+	}
+
+	// time ranges of each day, and days sharing the same time ranges in order of appearance
+	var ranges []string
+	daysByRanges := map[string][]int{}
+	for day := range weekDays {
+		var dayRanges []string
+		for hour := 0; hour < 24; hour++ {
+			if !authorized[day*24+hour] {
+				continue
+			}
+			start := hour
+			for hour < 24 && authorized[day*24+hour] {
+				hour++
+			}
+			dayRanges = append(dayRanges, fmt.Sprintf("%02d:00-%02d:00", start, hour))
+		}
+		if len(dayRanges) == 0 {
+			continue
+		}
+		key := strings.Join(dayRanges, ",")
+		if _, ok := daysByRanges[key]; !ok {
+			ranges = append(ranges, key)
+		}
+		daysByRanges[key] = append(daysByRanges[key], day)
+	}
+
+	entries := make([]string, 0, len(ranges))
+	for _, key := range ranges {
+		entries = append(entries, describeWeekDays(daysByRanges[key])+" "+key)
+	}
+	return strings.Join(entries, "; "), nil
+}
+
+// describeWeekDays formats sorted day indexes, grouping consecutive days into ranges such as "Mon-Wed,Fri"
+func describeWeekDays(days []int) string {
+	var parts []string
+	for i := 0; i < len(days); i++ {
+		first := days[i]
+		for i+1 < len(days) && days[i+1] == days[i]+1 {
+			i++
+		}
+		if days[i] == first {
+			parts = append(parts, weekDays[first])
+		} else {
+			parts = append(parts, weekDays[first]+"-"+weekDays[days[i]])
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// normalizeHoursOfWeek returns a sorted copy of the hour slots without duplicates
+func normalizeHoursOfWeek(hours []int) []int {
+	normalized := slices.Clone(hours)
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
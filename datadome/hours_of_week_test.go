@@ -0,0 +1,231 @@
+package datadome
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeeklyScheduleHours(t *testing.T) {
+	winter := time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC)
+	summer := time.Date(2025, time.July, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		schedule  weeklySchedule
+		reference time.Time
+		expected  []int
+	}{
+		{
+			name:      "UTC",
+			schedule:  weeklySchedule{Days: []string{"Mon", "Tue"}, Start: "09:00", End: "11:00", Timezone: "UTC"},
+			reference: winter,
+			expected:  []int{9, 10, 33, 34},
+		},
+		{
+			name:      "case insensitive days",
+			schedule:  weeklySchedule{Days: []string{"sat"}, Start: "00:00", End: "24:00", Timezone: "UTC"},
+			reference: winter,
+			expected:  []int{120, 121, 122, 123, 124, 125, 126, 127, 128, 129, 130, 131, 132, 133, 134, 135, 136, 137, 138, 139, 140, 141, 142, 143},
+		},
+		{
+			name:      "standard time",
+			schedule:  weeklySchedule{Days: []string{"Mon", "Fri"}, Start: "08:00", End: "10:00", Timezone: "Europe/Paris"},
+			reference: winter,
+			expected:  []int{7, 8, 103, 104},
+		},
+		{
+			name:      "daylight saving time",
+			schedule:  weeklySchedule{Days: []string{"Mon", "Fri"}, Start: "08:00", End: "10:00", Timezone: "Europe/Paris"},
+			reference: summer,
+			expected:  []int{6, 7, 102, 103},
+		},
+		{
+			name:      "wraps over midnight",
+			schedule:  weeklySchedule{Days: []string{"Sun"}, Start: "22:00", End: "02:00", Timezone: "UTC"},
+			reference: winter,
+			expected:  []int{0, 1, 166, 167},
+		},
+		{
+			name:      "wraps over the week",
+			schedule:  weeklySchedule{Days: []string{"Mon"}, Start: "00:00", End: "01:00", Timezone: "Europe/Paris"},
+			reference: winter,
+			expected:  []int{167},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			hours, err := test.schedule.hours(test.reference)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, hours)
+		})
+	}
+}
+
+func TestWeeklyScheduleHours_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule weeklySchedule
+		expected string
+	}{
+		{
+			name:     "unknown day",
+			schedule: weeklySchedule{Days: []string{"Monday"}, Start: "09:00", End: "11:00", Timezone: "UTC"},
+			expected: `invalid day of the week "Monday"`,
+		},
+		{
+			name:     "minutes",
+			schedule: weeklySchedule{Days: []string{"Mon"}, Start: "09:30", End: "11:00", Timezone: "UTC"},
+			expected: `authorized hours must start and end on the hour`,
+		},
+		{
+			name:     "start at end of day",
+			schedule: weeklySchedule{Days: []string{"Mon"}, Start: "24:00", End: "11:00", Timezone: "UTC"},
+			expected: `expected an hour between 00:00 and 23:00`,
+		},
+		{
+			name:     "empty range",
+			schedule: weeklySchedule{Days: []string{"Mon"}, Start: "09:00", End: "09:00", Timezone: "UTC"},
+			expected: `the end time must differ from the start time`,
+		},
+		{
+			name:     "unknown time zone",
+			schedule: weeklySchedule{Days: []string{"Mon"}, Start: "09:00", End: "11:00", Timezone: "Mars/Olympus_Mons"},
+			expected: `invalid time zone "Mars/Olympus_Mons"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.schedule.hours(time.Now())
+			assert.ErrorContains(t, err, test.expected)
+		})
+	}
+}
+
+func TestExpandWeeklySchedules(t *testing.T) {
+	schedules := []weeklySchedule{
+		{Days: []string{"Mon"}, Start: "09:00", End: "12:00", Timezone: "UTC"},
+		{Days: []string{"Mon"}, Start: "11:00", End: "13:00", Timezone: "UTC"},
+	}
+
+	hours, err := expandWeeklySchedules(schedules, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{9, 10, 11, 12}, hours)
+}
+
+func TestParseHoursOfWeek(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		expected    []weeklySchedule
+	}{
+		{
+			name:        "range of days",
+			description: "Mon-Fri 08:00-20:00",
+			expected: []weeklySchedule{
+				{Days: []string{"Mon", "Tue", "Wed", "Thu", "Fri"}, Start: "08:00", End: "20:00", Timezone: "Europe/Paris"},
+			},
+		},
+		{
+			name:        "range of days wrapping over the week",
+			description: "fri-mon 22:00-02:00",
+			expected: []weeklySchedule{
+				{Days: []string{"Fri", "Sat", "Sun", "Mon"}, Start: "22:00", End: "02:00", Timezone: "Europe/Paris"},
+			},
+		},
+		{
+			name:        "several days, time ranges and entries",
+			description: "Mon, Wed-Thu 08:00-12:00, 14:00-18:00; Sat 10:00-12:00;",
+			expected: []weeklySchedule{
+				{Days: []string{"Mon", "Wed", "Thu"}, Start: "08:00", End: "12:00", Timezone: "Europe/Paris"},
+				{Days: []string{"Mon", "Wed", "Thu"}, Start: "14:00", End: "18:00", Timezone: "Europe/Paris"},
+				{Days: []string{"Sat"}, Start: "10:00", End: "12:00", Timezone: "Europe/Paris"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			schedules, err := parseHoursOfWeek(test.description, "Europe/Paris")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, schedules)
+		})
+	}
+}
+
+func TestParseHoursOfWeek_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"":                       `no hours found`,
+		"08:00-20:00":            `expected days followed by time ranges`,
+		"Mon-Funday 08:00-20:00": `invalid day of the week "Funday"`,
+		"Mon 08:00":              `invalid time range "08:00"`,
+	}
+
+	for description, expected := range tests {
+		t.Run(description, func(t *testing.T) {
+			_, err := parseHoursOfWeek(description, "UTC")
+			assert.ErrorContains(t, err, expected)
+		})
+	}
+}
+
+func TestDescribeHoursOfWeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		hours    []int
+		expected string
+	}{
+		{
+			name:     "no hours",
+			hours:    nil,
+			expected: "",
+		},
+		{
+			name:     "days sharing the same time ranges",
+			hours:    []int{8, 9, 32, 33, 80, 81, 130, 131},
+			expected: "Mon-Tue,Thu 08:00-10:00; Sat 10:00-12:00",
+		},
+		{
+			name:     "several time ranges and end of day",
+			hours:    []int{8, 9, 14, 22, 23, 22, 14},
+			expected: "Mon 08:00-10:00,14:00-15:00,22:00-24:00",
+		},
+		{
+			name:     "whole week",
+			hours:    normalizeHoursOfWeek(expandAllHours()),
+			expected: "Mon-Sun 00:00-24:00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			description, err := describeHoursOfWeek(test.hours)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, description)
+		})
+	}
+}
+
+func TestDescribeHoursOfWeek_RoundTrip(t *testing.T) {
+	hours := []int{0, 1, 2, 30, 31, 32, 50, 100, 101, 120, 167}
+
+	description, err := describeHoursOfWeek(hours)
+	assert.NoError(t, err)
+
+	schedules, err := parseHoursOfWeek(description, "UTC")
+	assert.NoError(t, err)
+	expanded, err := expandWeeklySchedules(schedules, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, hours, expanded)
+}
+
+func expandAllHours() []int {
+	hours := make([]int, hoursInWeek)
+	for i := range hours {
+		hours[i] = i
+	}
+	return hours
+}
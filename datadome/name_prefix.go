@@ -0,0 +1,15 @@
+package datadome
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resolveResourceName returns the name of a resource to create: its name when it is set,
+// otherwise its name_prefix followed by a unique suffix, which is then kept in the state.
+func resolveResourceName(data *schema.ResourceData) string {
+	if name, ok := data.GetOk("name"); ok {
+		return name.(string)
+	}
+	return id.PrefixedUniqueId(data.Get("name_prefix").(string))
+}
@@ -0,0 +1,22 @@
+package datadome
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveResourceName(t *testing.T) {
+	resource := resourceCustomRule()
+
+	data := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{"name": "my-rule"})
+	assert.Equal(t, "my-rule", resolveResourceName(data))
+
+	data = schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{"name_prefix": "my-rule-"})
+	first := resolveResourceName(data)
+	second := resolveResourceName(data)
+	assert.True(t, strings.HasPrefix(first, "my-rule-"))
+	assert.NotEqual(t, first, second)
+}
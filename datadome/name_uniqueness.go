@@ -0,0 +1,76 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/datadome/terraform-provider/common"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// priorResourceID returns the ID of the resource in the prior state, or an empty string when it is created.
+// The ID of a resource being replaced is only known by its raw state, as CustomizeDiff then runs without state.
+func priorResourceID(data *schema.ResourceDiff) string {
+	if data.Id() != "" {
+		return data.Id()
+	}
+	rawState := data.GetRawState()
+	if rawState.IsNull() || !rawState.IsKnown() {
+		return ""
+	}
+	id := rawState.GetAttr("id")
+	if id.IsNull() || !id.IsKnown() {
+		return ""
+	}
+	return id.AsString()
+}
+
+// listOnce returns a function listing the objects of the account on its first call only,
+// so the checks of a plan share a single list request
+func listOnce[T any](ctx context.Context, c common.ListAPI[T]) func() ([]T, error) {
+	var objects []T
+	var err error
+	listed := false
+	return func() ([]T, error) {
+		if !listed {
+			objects, err = c.List(ctx)
+			listed = true
+		}
+		return objects, err
+	}
+}
+
+// checkNameAvailable raises an error when the planned name of a resource is already used by another object of the account,
+// listed by list and identified by the given function. The check only runs when the name is set or changed,
+// and not when the resource adopts the existing object of the same name.
+func checkNameAvailable[T any](data *schema.ResourceDiff, list func() ([]T, error), kind string, identify func(T) (name, id string)) error {
+	if data.Get("adopt_existing").(bool) {
+		return nil
+	}
+	if data.Id() != "" && !data.HasChange("name") {
+		return nil
+	}
+	// the name generated from name_prefix is only known on apply, and is unique
+	if !data.NewValueKnown("name") {
+		return nil
+	}
+	name := data.Get("name").(string)
+	if name == "" {
+		return nil
+	}
+
+	objects, err := list()
+	if err != nil {
+		log.Printf("[WARN] unable to list the %ss to check that the name %q is available: %s", kind, name, err)
+		return nil
+	}
+
+	ownID := priorResourceID(data)
+	for _, object := range objects {
+		if objectName, id := identify(object); objectName == name && id != ownID {
+			return fmt.Errorf("name: %q is already used by the %s with the ID %s, set adopt_existing to take ownership of it or choose another name", name, kind, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckNameAvailable_customRule(t *testing.T) {
+	mockClient := dd.NewMockClientCustomRule()
+	existingID := 1234
+	_, err := mockClient.Create(context.Background(), dd.CustomRule{
+		ID:       &existingID,
+		Name:     "acc-test",
+		Query:    "ip: 10.0.0.1",
+		Response: "block",
+		Priority: "high",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ProviderConfig{ClientCustomRule: mockClient}
+	customRule := map[string]interface{}{
+		"name":     "acc-test",
+		"query":    "ip: 192.168.0.1",
+		"response": "block",
+	}
+
+	resp := testPlanCustomRule(t, config, customRule)
+
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityError, resp.Diagnostics[0].Severity)
+		assert.Contains(t, resp.Diagnostics[0].Summary, `name: "acc-test" is already used by the custom rule with the ID 1234`)
+	}
+
+	customRule["adopt_existing"] = true
+	resp = testPlanCustomRule(t, config, customRule)
+
+	assert.Empty(t, resp.Diagnostics)
+}
+
+func TestCheckAdoptedEndpoint(t *testing.T) {
+	mockClient := dd.NewMockClientEndpoint()
+	existingID := "9d3b3f5e-1c2a-4b6e-8f0a-2e4d6c8b0a1f"
+	domain := "example.com"
+	_, err := mockClient.Create(context.Background(), dd.Endpoint{
+		ID:           &existingID,
+		Name:         "acc-test",
+		Source:       "Api",
+		TrafficUsage: "General",
+		Domain:       &domain,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ProviderConfig{ClientEndpoint: mockClient}
+	endpoint := map[string]interface{}{
+		"name":           "acc-test",
+		"source":         "Web Browser",
+		"traffic_usage":  "Login",
+		"domain":         "example.com",
+		"adopt_existing": true,
+	}
+
+	resp := testPlanResource(t, config, "datadome_endpoint", endpoint)
+
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, resp.Diagnostics[0].Severity)
+		assert.Equal(t, "Adopted endpoint with another routing", resp.Diagnostics[0].Summary)
+	}
+
+	endpoint["source"] = "Api"
+	endpoint["traffic_usage"] = "General"
+	resp = testPlanResource(t, config, "datadome_endpoint", endpoint)
+
+	assert.Empty(t, resp.Diagnostics)
+}
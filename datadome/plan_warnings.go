@@ -0,0 +1,95 @@
+package datadome
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// planWarningsKey is the context key of the warnings collected while planning a resource change
+type planWarningsKey struct{}
+
+// addPlanWarning reports a warning raised while planning a resource change, typically from a CustomizeDiff
+// function which can only return errors. The warning is always logged, and is also shown by Terraform
+// when the provider is served by ProviderServer.
+func addPlanWarning(ctx context.Context, summary, detail string) {
+	log.Printf("[WARN] %s: %s", summary, detail)
+
+	warnings, ok := ctx.Value(planWarningsKey{}).(*[]*tfprotov5.Diagnostic)
+	if !ok {
+		return
+	}
+
+	// CustomizeDiff runs a second time when the resource must be replaced
+	warning := &tfprotov5.Diagnostic{
+		Severity: tfprotov5.DiagnosticSeverityWarning,
+		Summary:  summary,
+		Detail:   detail,
+	}
+	if slices.ContainsFunc(*warnings, func(d *tfprotov5.Diagnostic) bool {
+		return d.Summary == warning.Summary && d.Detail == warning.Detail
+	}) {
+		return
+	}
+	*warnings = append(*warnings, warning)
+}
+
+// planFinding is a problem found by a plan-time check
+type planFinding struct {
+	Summary string
+	Detail  string
+}
+
+// reportPlanFindings reports the findings of a plan-time check as warnings,
+// or as an error when strict_plan_checks is enabled on the provider
+func reportPlanFindings(ctx context.Context, meta interface{}, findings []planFinding) error {
+	if len(findings) == 0 {
+		return nil
+	}
+
+	if config, ok := meta.(*ProviderConfig); ok && config.StrictPlanChecks {
+		errs := make([]error, len(findings))
+		for i, finding := range findings {
+			errs[i] = fmt.Errorf("%s: %s", finding.Summary, finding.Detail)
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, finding := range findings {
+		addPlanWarning(ctx, finding.Summary, finding.Detail)
+	}
+	return nil
+}
+
+// planWarningsServer adds the warnings collected while planning a resource change to the diagnostics of the plan
+type planWarningsServer struct {
+	tfprotov5.ProviderServer
+}
+
+func (s *planWarningsServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	var warnings []*tfprotov5.Diagnostic
+
+	resp, err := s.ProviderServer.PlanResourceChange(context.WithValue(ctx, planWarningsKey{}, &warnings), req)
+	if resp != nil {
+		resp.Diagnostics = append(resp.Diagnostics, warnings...)
+	}
+
+	return resp, err
+}
+
+// newPlanWarningsServer returns the gRPC server of the given provider, showing the warnings raised while planning resource changes
+func newPlanWarningsServer(p *schema.Provider) tfprotov5.ProviderServer {
+	return &planWarningsServer{
+		ProviderServer: schema.NewGRPCProviderServer(p),
+	}
+}
+
+// ProviderServer returns the gRPC server of Provider, showing the warnings raised while planning resource changes
+func ProviderServer() tfprotov5.ProviderServer {
+	return newPlanWarningsServer(Provider())
+}
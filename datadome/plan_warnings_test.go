@@ -0,0 +1,108 @@
+package datadome
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/stretchr/testify/assert"
+)
+
+// testPlanCustomRule plans the creation of a custom rule with the given configuration through newPlanWarningsServer
+func testPlanCustomRule(t *testing.T, config *ProviderConfig, customRule map[string]interface{}) *tfprotov5.PlanResourceChangeResponse {
+	return testPlanResource(t, config, "datadome_custom_rule", customRule)
+}
+
+// testPlanResource plans the creation of a resource with the given configuration through newPlanWarningsServer
+func testPlanResource(t *testing.T, config *ProviderConfig, typeName string, resource map[string]interface{}) *tfprotov5.PlanResourceChangeResponse {
+	p := Provider()
+	p.SetMeta(config)
+	server := newPlanWarningsServer(p)
+
+	ty := p.ResourcesMap[typeName].CoreConfigSchema().ImpliedType()
+	rawConfig, err := json.Marshal(resource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configValue, err := ctyjson.Unmarshal(rawConfig, ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dynamicValue := func(value cty.Value) *tfprotov5.DynamicValue {
+		packed, err := msgpack.Marshal(value, ty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &tfprotov5.DynamicValue{MsgPack: packed}
+	}
+
+	resp, err := server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       dynamicValue(cty.NullVal(ty)),
+		ProposedNewState: dynamicValue(configValue),
+		Config:           dynamicValue(configValue),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestPlanWarnings_unknownOverriddenBot(t *testing.T) {
+	config := &ProviderConfig{
+		ClientCustomRule: dd.NewMockClientCustomRule(),
+		ClientVerifiedBot: dd.NewMockClientVerifiedBot(dd.VerifiedBot{
+			UUID:     "550e8400-e29b-41d4-a716-446655440000",
+			Name:     "Googlebot",
+			Category: "Search Engine",
+		}),
+	}
+	customRule := map[string]interface{}{
+		"name":          "acc-test",
+		"query":         "ip: 192.168.0.1",
+		"response":      "allow",
+		"endpoint_type": "web",
+		"priority":      "low",
+		"overridden_bot": []interface{}{
+			map[string]interface{}{"uuid": "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		},
+	}
+
+	resp := testPlanCustomRule(t, config, customRule)
+
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityWarning, resp.Diagnostics[0].Severity)
+		assert.Equal(t, "Unknown overridden bot", resp.Diagnostics[0].Summary)
+		assert.Contains(t, resp.Diagnostics[0].Detail, "6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	}
+}
+
+func TestPlanWarnings_knownOverriddenBot(t *testing.T) {
+	config := &ProviderConfig{
+		ClientCustomRule: dd.NewMockClientCustomRule(),
+		ClientVerifiedBot: dd.NewMockClientVerifiedBot(dd.VerifiedBot{
+			UUID:     "550e8400-e29b-41d4-a716-446655440000",
+			Name:     "Googlebot",
+			Category: "Search Engine",
+		}),
+	}
+	customRule := map[string]interface{}{
+		"name":          "acc-test",
+		"query":         "ip: 192.168.0.1",
+		"response":      "allow",
+		"endpoint_type": "web",
+		"priority":      "low",
+		"overridden_bot": []interface{}{
+			map[string]interface{}{"uuid": "550e8400-e29b-41d4-a716-446655440000"},
+		},
+	}
+
+	resp := testPlanCustomRule(t, config, customRule)
+
+	assert.Empty(t, resp.Diagnostics)
+}
@@ -9,9 +9,15 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// importByNamePrefix is the prefix of the import IDs resolving the resource by its name instead of its ID
+const importByNamePrefix = "name:"
+
 type ProviderConfig struct {
-	ClientCustomRule common.API[datadome.CustomRule, int]
-	ClientEndpoint   common.API[datadome.Endpoint, string]
+	ClientCustomRule  common.API[datadome.CustomRule, int]
+	ClientEndpoint    common.API[datadome.Endpoint, string]
+	ClientVerifiedBot common.ListAPI[datadome.VerifiedBot]
+	// StrictPlanChecks turns the warnings of the plan-time checks into errors
+	StrictPlanChecks bool
 }
 
 // Provider of DataDome
@@ -29,12 +35,23 @@ func Provider() *schema.Provider {
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("DATADOME_APIKEY", nil),
 			},
+			"strict_plan_checks": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"datadome_custom_rule": resourceCustomRule(),
-			"datadome_endpoint":    resourceEndpoint(),
+			"datadome_custom_rule":                resourceCustomRule(),
+			"datadome_endpoint":                   resourceEndpoint(),
+			"datadome_ip_rule_set":                resourceIPRuleSet(),
+			"datadome_custom_rule_set":            resourceCustomRuleSet(),
+			"datadome_multi_endpoint_custom_rule": resourceMultiEndpointCustomRule(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"datadome_verified_bots":          dataSourceVerifiedBots(),
+			"datadome_endpoint_match":         dataSourceEndpointMatch(),
+			"datadome_custom_rule_evaluation": dataSourceCustomRuleEvaluation(),
 		},
-		DataSourcesMap:       map[string]*schema.Resource{},
 		ConfigureContextFunc: providerConfigure,
 	}
 }
@@ -88,8 +105,21 @@ func providerConfigure(ctx context.Context, data *schema.ResourceData) (interfac
 		return nil, diags
 	}
 
+	clientVerifiedBot, err := datadome.NewClientVerifiedBot(host, apikey)
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create DataDome client for verified bots",
+			Detail:   "Unable to authenticate user for authenticated DataDome client",
+		})
+
+		return nil, diags
+	}
+
 	return &ProviderConfig{
-		ClientCustomRule: clientCustomRule,
-		ClientEndpoint:   clientEndpoint,
+		ClientCustomRule:  clientCustomRule,
+		ClientEndpoint:    clientEndpoint,
+		ClientVerifiedBot: clientVerifiedBot,
+		StrictPlanChecks:  data.Get("strict_plan_checks").(bool),
 	}, diags
 }
@@ -0,0 +1,66 @@
+package datadome
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+// frameworkProvider serves the provider-defined functions, which are not supported by the SDK provider.
+// Both providers are muxed together, so the provider schema must stay identical to the one of Provider.
+type frameworkProvider struct{}
+
+var _ provider.ProviderWithFunctions = &frameworkProvider{}
+
+// FrameworkProvider of DataDome, exposing the provider-defined functions
+func FrameworkProvider() provider.Provider {
+	return &frameworkProvider{}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "datadome"
+}
+
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = fwschema.Schema{
+		Attributes: map[string]fwschema.Attribute{
+			"host": fwschema.StringAttribute{
+				Optional: true,
+			},
+			"apikey": fwschema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+			},
+			"strict_plan_checks": fwschema.BoolAttribute{
+				Optional: true,
+			},
+		},
+	}
+}
+
+// Configure does nothing as the functions do not call the DataDome API, the clients are configured by Provider
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		newHoursOfWeekFunction,
+		newDescribeHoursFunction,
+		newQueryInFunction,
+		newQueryAndFunction,
+		newQueryOrFunction,
+		newQueryEscapeFunction,
+	}
+}
@@ -5,9 +5,14 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -43,6 +48,27 @@ func TestProvider_impl(t *testing.T) {
 	_ = Provider()
 }
 
+// TestFrameworkProvider_mux test that the SDK and framework providers can be served together
+func TestFrameworkProvider_mux(t *testing.T) {
+	ctx := context.Background()
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		ProviderServer,
+		providerserver.NewProtocol5(FrameworkProvider()),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	resp, err := muxServer.ProviderServer().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.Diagnostics)
+	assert.Contains(t, resp.ResourceSchemas, "datadome_custom_rule")
+	assert.Contains(t, resp.ResourceSchemas, "datadome_endpoint")
+	assert.Contains(t, resp.Functions, "hours_of_week")
+	assert.Contains(t, resp.Functions, "describe_hours")
+}
+
 func TestProviderConfigure(t *testing.T) {
 	t.Run("With apiKey (direct)", func(t *testing.T) {
 		apiKey := "valid_api_key"
@@ -206,6 +232,32 @@ func testAccImportStateIdFunc(resourceName string) resource.ImportStateIdFunc {
 	}
 }
 
+// testAccStoreResourceID stores the ID of the given resourceName into id
+func testAccStoreResourceID(resourceName string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %q", resourceName)
+		}
+		*id = rs.Primary.ID
+		return nil
+	}
+}
+
+// testAccCheckResourceIDUnchanged checks that the ID of the given resourceName is still equal to id
+func testAccCheckResourceIDUnchanged(resourceName string, id *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %q", resourceName)
+		}
+		if rs.Primary.ID != *id {
+			return fmt.Errorf("resource %q has been recreated: ID changed from %q to %q", resourceName, *id, rs.Primary.ID)
+		}
+		return nil
+	}
+}
+
 /*
 Resources CustomRules tests
 */
@@ -448,8 +500,38 @@ func TestAccCustomRuleResource_withDates(t *testing.T) {
 	})
 }
 
-// TestAccCustomRuleResource_update test the creation of a new custom rule and update it
-func TestAccCustomRuleResource_update(t *testing.T) {
+const testAccCustomRuleResourceConfigWithRFC3339Dates = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+  enabled       = true
+  activated_at  = "2080-02-01T01:59:59+02:00"
+  expired_at    = "2099-12-31T23:59:59Z"
+}
+`
+
+const testAccCustomRuleResourceConfigWithPastDates = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+  enabled       = true
+  activated_at  = "2020-01-01T01:00:00+01:00"
+  expired_at    = "2099-12-31 23:59:59"
+}
+`
+
+// TestAccCustomRuleResource_withRFC3339Dates test that RFC 3339 dates are normalized into the format of the API
+func TestAccCustomRuleResource_withRFC3339Dates(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -463,32 +545,42 @@ func TestAccCustomRuleResource_update(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccCustomRuleResourceConfig,
+				Config: testAccCustomRuleResourceConfigWithRFC3339Dates,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", "ip: 192.168.0.1"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "response", "allow"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "endpoint_type", "web"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "low"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "activated_at", "2080-01-31 23:59:59"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "expired_at", "2099-12-31 23:59:59"),
 				),
 			},
 			{
-				Config: testAccCustomRuleResourceConfigUpdate,
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test-updated"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "normal"),
-				),
+				Config:   testAccCustomRuleResourceConfigWithDates,
+				PlanOnly: true,
 			},
 		},
 	})
 }
 
-// TestAccCustomRuleResource_delete test the creation of a new custom rule and delete it
-func TestAccCustomRuleResource_delete(t *testing.T) {
+// TestAccCustomRuleResource_withPastDates test that a custom rule whose activation date has passed can still be planned
+func TestAccCustomRuleResource_withPastDates(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
+	existingID := 1234
+	enabled := true
+	activatedAt := "2020-01-01 00:00:00"
+	expiredAt := "2099-12-31 23:59:59"
+	_, err := mockClient.Create(context.Background(), datadome.CustomRule{
+		ID:           &existingID,
+		Name:         "acc-test",
+		Query:        "ip: 192.168.0.1",
+		Response:     "allow",
+		EndpointType: "web",
+		Priority:     "low",
+		Enabled:      &enabled,
+		ActivatedAt:  &activatedAt,
+		ExpiredAt:    &expiredAt,
+	})
+	if err != nil {
+		t.Fatalf("fail to create existing custom rule: %s", err)
+	}
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
@@ -501,29 +593,79 @@ func TestAccCustomRuleResource_delete(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccCustomRuleResourceConfig,
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", "ip: 192.168.0.1"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "response", "allow"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "endpoint_type", "web"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "low"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
-				),
+				Config:             testAccCustomRuleResourceConfigWithPastDates,
+				ResourceName:       "datadome_custom_rule.accConfig",
+				ImportState:        true,
+				ImportStateId:      "1234",
+				ImportStatePersist: true,
 			},
 			{
-				Config: `provider "datadome" {}`,
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceDoesNotExists("datadome_custom_rule.accConfig"),
-				),
+				Config:   testAccCustomRuleResourceConfigWithPastDates,
+				PlanOnly: true,
 			},
 		},
 	})
 }
 
-// TestAccCustomRuleResource_import tests the creation of a custom rule and its importation in another configuration
-func TestAccCustomRuleResource_import(t *testing.T) {
+const testAccCustomRuleResourceConfigWithRelativeDates = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "block"
+  endpoint_type = "web"
+  priority      = "low"
+  activates_in  = "1h"
+  expires_in    = "48h"
+}
+`
+
+const testAccCustomRuleResourceConfigWithRelativeDatesUpdate = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "block"
+  endpoint_type = "web"
+  priority      = "low"
+  activates_in  = "1h"
+  expires_in    = "72h"
+}
+`
+
+const testAccCustomRuleResourceConfigWithWrongRelativeDates = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "block"
+  endpoint_type = "web"
+  priority      = "low"
+  activates_in  = "72h"
+  expires_in    = "48h"
+}
+`
+
+// testAccCheckCustomRuleDateIn checks that the date of the given key is about duration after the current time
+func testAccCheckCustomRuleDateIn(resourceName, key string, duration time.Duration) resource.TestCheckFunc {
+	return resource.TestCheckResourceAttrWith(resourceName, key, func(value string) error {
+		date, err := time.Parse(time.DateTime, value)
+		if err != nil {
+			return err
+		}
+		expected := time.Now().UTC().Add(duration)
+		if date.Before(expected.Add(-5*time.Minute)) || date.After(expected.Add(5*time.Minute)) {
+			return fmt.Errorf("expected %s to be about %s, got %s", key, expected.Format(time.DateTime), value)
+		}
+		return nil
+	})
+}
+
+// TestAccCustomRuleResource_withRelativeDates test the creation and the update of a custom rule with activates_in and expires_in durations
+func TestAccCustomRuleResource_withRelativeDates(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -537,96 +679,80 @@ func TestAccCustomRuleResource_import(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccCustomRuleResourceConfig,
+				Config: testAccCustomRuleResourceConfigWithRelativeDates,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
-					resource.TestCheckResourceAttrSet("datadome_custom_rule.accConfig", "id"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "activates_in", "1h"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "expires_in", "48h"),
+					testAccCheckCustomRuleDateIn("datadome_custom_rule.accConfig", "activated_at", time.Hour),
+					testAccCheckCustomRuleDateIn("datadome_custom_rule.accConfig", "expired_at", 48*time.Hour),
 				),
 			},
 			{
-				Config:            ``,
-				ResourceName:      "datadome_custom_rule.accConfig",
-				ImportState:       true,
-				ImportStateIdFunc: testAccImportStateIdFunc("datadome_custom_rule.accConfig"),
-				ImportStateVerify: true,
+				Config:   testAccCustomRuleResourceConfigWithRelativeDates,
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCustomRuleResourceConfigWithRelativeDatesUpdate,
 				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", "ip: 192.168.0.1"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "response", "allow"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "endpoint_type", "web"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "low"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "expires_in", "72h"),
+					testAccCheckCustomRuleDateIn("datadome_custom_rule.accConfig", "activated_at", time.Hour),
+					testAccCheckCustomRuleDateIn("datadome_custom_rule.accConfig", "expired_at", 72*time.Hour),
 				),
 			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithWrongRelativeDates,
+				ExpectError: regexp.MustCompile(`expires_in duration must be greater than activates_in duration`),
+			},
 		},
 	})
 }
 
-// TestAccCustomRuleResource_wrongParameters test the creation with wrong parameters (i.e. response, endpoint, and priority)
-func TestAccCustomRuleResource_wrongParameters(t *testing.T) {
-	mockClient := datadome.NewMockClientCustomRule()
+const testAccCustomRuleResourceConfigExpiring = `
+provider "datadome" {}
 
-	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-		return &ProviderConfig{
-			ClientCustomRule: mockClient,
-		}, nil
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "block"
+  endpoint_type = "web"
+  priority      = "low"
+  expires_in    = "1h"
+}
+`
+
+// testAccExpireCustomRules sets the expiration date of all the custom rules of the mock client in the past
+func testAccExpireCustomRules(t *testing.T, mockClient *datadome.MockClientCustomRule) {
+	customRules, err := mockClient.List(context.Background())
+	if err != nil {
+		t.Fatalf("fail to list custom rules: %s", err)
+	}
+	expiredAt := "2020-01-01 00:00:00"
+	for _, customRule := range customRules {
+		customRule.ExpiredAt = &expiredAt
+		if _, err = mockClient.Update(context.Background(), customRule); err != nil {
+			t.Fatalf("fail to expire custom rule: %s", err)
+		}
 	}
+}
 
-	resource.Test(t, resource.TestCase{
-		PreCheck:          func() { testAccResourcePreCheck(t) },
-		ProviderFactories: testAccProviders,
-		Steps: []resource.TestStep{
-			{
-				Config:      testAccCustomRuleResourceConfigWrongResponse,
-				ExpectError: regexp.MustCompile(`expected response to be one of \[.*\], got wrong_response`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWrongEndpoint,
-				ExpectError: regexp.MustCompile(`expected endpoint_type to be one of \[.*\], got wrong_endpoint`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWrongPriority,
-				ExpectError: regexp.MustCompile(`expected priority to be one of \[.*\], got wrong_priority`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigEmptyName,
-				ExpectError: regexp.MustCompile(`expected "name" to not be an empty string or whitespace`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigBlankName,
-				ExpectError: regexp.MustCompile(`expected "name" to not be an empty string or whitespace`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigEmptyQuery,
-				ExpectError: regexp.MustCompile(`expected "query" to not be an empty string`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWithWrongDateFormat,
-				ExpectError: regexp.MustCompile(`(?s)date '2019-09-26T07:58:30\.996\+0200' does not match the required format.*'YYYY-MM-DD HH:MM:SS'`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWithActivationDateBeforeNow,
-				ExpectError: regexp.MustCompile(`date '1970-01-01 00:00:00' must not be in the past`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWithExpirationDateBeforeNow,
-				ExpectError: regexp.MustCompile(`date '1970-01-01 00:00:00' must not be in the past`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWithExpirationDateBeforeActivationDate,
-				ExpectError: regexp.MustCompile(`expired_at date must be after activated_at date`),
-			},
-		},
-	})
+// testAccCheckCustomRuleCount checks the number of custom rules stored by the mock client
+func testAccCheckCustomRuleCount(mockClient *datadome.MockClientCustomRule, expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		customRules, err := mockClient.List(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(customRules) != expected {
+			return fmt.Errorf("expected %d custom rules, got %d", expected, len(customRules))
+		}
+		return nil
+	}
 }
 
-// TestAccCustomRuleResource_createAlreadyExists test the creation when a custom rule already exists with the same name
-func TestAccCustomRuleResource_createAlreadyExists(t *testing.T) {
+// TestAccCustomRuleResource_expired test that an expired custom rule is kept in the account and in the state
+func TestAccCustomRuleResource_expired(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
-	mockClient.CreateFunc = func(ctx context.Context, params datadome.CustomRule) (*int, error) {
-		return nil, fmt.Errorf("The rule with name: 'acc-test' already exists")
-	}
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
@@ -639,19 +765,33 @@ func TestAccCustomRuleResource_createAlreadyExists(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config:      testAccCustomRuleResourceConfig,
-				ExpectError: regexp.MustCompile(`'acc-test' already exists`),
+				Config: testAccCustomRuleResourceConfigExpiring,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "is_expired", "false"),
+					testAccCheckCustomRuleCount(mockClient, 1),
+				),
+			},
+			{
+				PreConfig: func() { testAccExpireCustomRules(t, mockClient) },
+				Config:    testAccCustomRuleResourceConfigExpiring,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "is_expired", "true"),
+					testAccCheckCustomRuleCount(mockClient, 1),
+				),
+			},
+			{
+				Config:   testAccCustomRuleResourceConfigExpiring,
+				PlanOnly: true,
 			},
 		},
 	})
 }
 
-// TestAccCustomRuleResource_updateAlreadyExists test the update when a custom rule already exists with the same name
-func TestAccCustomRuleResource_updateAlreadyExists(t *testing.T) {
+// TestAccCustomRuleResource_update test the creation of a new custom rule and update it
+func TestAccCustomRuleResource_update(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
-	mockClient.UpdateFunc = func(ctx context.Context, params datadome.CustomRule) (*datadome.CustomRule, error) {
-		return nil, fmt.Errorf("Another rule with name: 'acc-test-updated' already exists")
-	}
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
@@ -668,19 +808,26 @@ func TestAccCustomRuleResource_updateAlreadyExists(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
 					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", "ip: 192.168.0.1"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "response", "allow"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "endpoint_type", "web"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "low"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
 				),
 			},
 			{
-				Config:      testAccCustomRuleResourceConfigUpdate,
-				ExpectError: regexp.MustCompile(`'acc-test-updated' already exists`),
+				Config: testAccCustomRuleResourceConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test-updated"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "normal"),
+				),
 			},
 		},
 	})
 }
 
-// Config consts for overridden_bot and policy_options tests
-
-const testAccCustomRuleResourceConfigWithOverriddenBot = `
+const testAccCustomRuleResourceConfigDisabled = `
 provider "datadome" {}
 
 resource "datadome_custom_rule" "accConfig" {
@@ -689,70 +836,11 @@ resource "datadome_custom_rule" "accConfig" {
   response      = "allow"
   endpoint_type = "web"
   priority      = "low"
+  enabled       = false
+}
+`
 
-  overridden_bot {
-    uuid = "550e8400-e29b-41d4-a716-446655440000"
-  }
-}
-`
-
-const testAccCustomRuleResourceConfigWithRateLimit = `
-provider "datadome" {}
-
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
-
-  policy_options {
-    rate_limit {
-      applies_to               = "ip"
-      threshold                = 100
-      time_frame               = "15m"
-      response_after_threshold = "block"
-    }
-  }
-}
-`
-
-const testAccCustomRuleResourceConfigWithTimeBox = `
-provider "datadome" {}
-
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
-
-  policy_options {
-    time_box {
-      authorized_hours_of_the_week = [9, 10, 11, 12, 13, 14, 15, 16, 17]
-      response_outside_time_box    = "block"
-    }
-  }
-}
-`
-
-const testAccCustomRuleResourceConfigWithInvalidUUID = `
-provider "datadome" {}
-
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
-
-  overridden_bot {
-    uuid = "not-a-uuid"
-  }
-}
-`
-
-const testAccCustomRuleResourceConfigWithInvalidThreshold = `
+const testAccCustomRuleResourceConfigWithoutEnabled = `
 provider "datadome" {}
 
 resource "datadome_custom_rule" "accConfig" {
@@ -761,102 +849,91 @@ resource "datadome_custom_rule" "accConfig" {
   response      = "allow"
   endpoint_type = "web"
   priority      = "low"
-
-  policy_options {
-    rate_limit {
-      applies_to               = "ip"
-      threshold                = 0
-      time_frame               = "15m"
-      response_after_threshold = "block"
-    }
-  }
 }
 `
 
-const testAccCustomRuleResourceConfigWithInvalidTimeFrame = `
-provider "datadome" {}
-
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
-
-  policy_options {
-    rate_limit {
-      applies_to              = "ip"
-      threshold               = 100
-      time_frame              = "2h"
-      response_after_threshold = "block"
-    }
-  }
-}
-`
+// TestAccCustomRuleResource_toggleEnabled test that enabling and disabling a custom rule updates it in place
+func TestAccCustomRuleResource_toggleEnabled(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
 
-const testAccCustomRuleResourceConfigWithInvalidHour = `
-provider "datadome" {}
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
 
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
+	var id string
 
-  policy_options {
-    time_box {
-      authorized_hours_of_the_week = [168]
-      response_outside_time_box    = "block"
-    }
-  }
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
+					testAccStoreResourceID("datadome_custom_rule.accConfig", &id),
+				),
+			},
+			{
+				Config: testAccCustomRuleResourceConfigDisabled,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "false"),
+					testAccCheckResourceIDUnchanged("datadome_custom_rule.accConfig", &id),
+				),
+			},
+			{
+				Config: testAccCustomRuleResourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
+					testAccCheckResourceIDUnchanged("datadome_custom_rule.accConfig", &id),
+				),
+			},
+		},
+	})
 }
-`
 
-const testAccCustomRuleResourceConfigWithBothPolicies = `
-provider "datadome" {}
+// TestAccCustomRuleResource_enabledComputed test that the enabled value returned by the API is kept in the state when not configured
+func TestAccCustomRuleResource_enabledComputed(t *testing.T) {
+	// The API enables a custom rule by default when rule_enabled is omitted
+	storage := datadome.NewMockClientCustomRule()
+	mockClient := datadome.NewMockClientCustomRule()
+	mockClient.CreateFunc = func(ctx context.Context, params datadome.CustomRule) (*int, error) {
+		enabled := true
+		params.Enabled = &enabled
+		return storage.Create(ctx, params)
+	}
+	mockClient.ListFunc = storage.List
+	mockClient.ReadFunc = storage.Read
+	mockClient.UpdateFunc = storage.Update
+	mockClient.DeleteFunc = storage.Delete
 
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
 
-  policy_options {
-    rate_limit {
-      applies_to               = "ip"
-      threshold                = 100
-      time_frame               = "15m"
-      response_after_threshold = "block"
-    }
-    time_box {
-      authorized_hours_of_the_week = [9, 10]
-      response_outside_time_box    = "block"
-    }
-  }
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfigWithoutEnabled,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
+				),
+			},
+		},
+	})
 }
-`
 
-// TestAccCustomRuleResource_withOverriddenBot tests creation with a valid overridden_bot UUID and verifies both uuid and name are read back
-func TestAccCustomRuleResource_withOverriddenBot(t *testing.T) {
+// TestAccCustomRuleResource_delete test the creation of a new custom rule and delete it
+func TestAccCustomRuleResource_delete(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
 
-	mockClient.ReadFunc = func(ctx context.Context, id int) (*datadome.CustomRule, error) {
-		return &datadome.CustomRule{
-			ID:           &id,
-			Name:         "acc-test",
-			Response:     "allow",
-			Query:        "ip: 192.168.0.1",
-			EndpointType: "web",
-			Priority:     "low",
-			OverriddenBot: &datadome.OverriddenBot{
-				UUID: "550e8400-e29b-41d4-a716-446655440000",
-				Name: "My Test Bot",
-			},
-		}, nil
-	}
-
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
 			ClientCustomRule: mockClient,
@@ -868,19 +945,29 @@ func TestAccCustomRuleResource_withOverriddenBot(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccCustomRuleResourceConfigWithOverriddenBot,
+				Config: testAccCustomRuleResourceConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "overridden_bot.0.uuid", "550e8400-e29b-41d4-a716-446655440000"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "overridden_bot.0.name", "My Test Bot"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", "ip: 192.168.0.1"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "response", "allow"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "endpoint_type", "web"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "low"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
+				),
+			},
+			{
+				Config: `provider "datadome" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceDoesNotExists("datadome_custom_rule.accConfig"),
 				),
 			},
 		},
 	})
 }
 
-// TestAccCustomRuleResource_withRateLimit tests creation with a rate_limit policy_options block
-func TestAccCustomRuleResource_withRateLimit(t *testing.T) {
+// TestAccCustomRuleResource_import tests the creation of a custom rule and its importation in another configuration
+func TestAccCustomRuleResource_import(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -894,21 +981,34 @@ func TestAccCustomRuleResource_withRateLimit(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccCustomRuleResourceConfigWithRateLimit,
+				Config: testAccCustomRuleResourceConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.rate_limit.0.applies_to", "ip"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.rate_limit.0.threshold", "100"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.rate_limit.0.time_frame", "15m"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.rate_limit.0.response_after_threshold", "block"),
+					resource.TestCheckResourceAttrSet("datadome_custom_rule.accConfig", "id"),
+				),
+			},
+			{
+				Config:            ``,
+				ResourceName:      "datadome_custom_rule.accConfig",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIdFunc("datadome_custom_rule.accConfig"),
+				ImportStateVerify: true,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", "ip: 192.168.0.1"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "response", "allow"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "endpoint_type", "web"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "low"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "enabled", "true"),
 				),
 			},
 		},
 	})
 }
 
-// TestAccCustomRuleResource_withTimeBox tests creation with a time_box policy_options block
-func TestAccCustomRuleResource_withTimeBox(t *testing.T) {
+// TestAccCustomRuleResource_importByName tests the importation of a custom rule by its name
+func TestAccCustomRuleResource_importByName(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -922,132 +1022,268 @@ func TestAccCustomRuleResource_withTimeBox(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccCustomRuleResourceConfigWithTimeBox,
+				Config: testAccCustomRuleResourceConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.response_outside_time_box", "block"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.authorized_hours_of_the_week.#", "9"),
-					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.authorized_hours_of_the_week.0", "9"),
+					resource.TestCheckResourceAttrSet("datadome_custom_rule.accConfig", "id"),
 				),
 			},
+			{
+				Config:            ``,
+				ResourceName:      "datadome_custom_rule.accConfig",
+				ImportState:       true,
+				ImportStateId:     "name:acc-test",
+				ImportStateVerify: true,
+			},
+			{
+				Config:        ``,
+				ResourceName:  "datadome_custom_rule.accConfig",
+				ImportState:   true,
+				ImportStateId: "name:unknown-rule",
+				ExpectError:   regexp.MustCompile(`no custom rule found with name "unknown-rule"`),
+			},
+			{
+				Config:        ``,
+				ResourceName:  "datadome_custom_rule.accConfig",
+				ImportState:   true,
+				ImportStateId: "acc-test",
+				ExpectError:   regexp.MustCompile(`unexpected import ID "acc-test", expected a numeric custom rule ID or "name:<rule name>"`),
+			},
 		},
 	})
 }
 
-const testAccCustomRuleResourceConfigWithOverriddenBotAndNonAllTraffic = `
-provider "datadome" {}
+// TestAccCustomRuleResource_wrongParameters test the creation with wrong parameters (i.e. response, endpoint, and priority)
+func TestAccCustomRuleResource_wrongParameters(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
 
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
 
-  overridden_bot {
-    uuid = "550e8400-e29b-41d4-a716-446655440000"
-  }
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCustomRuleResourceConfigWrongResponse,
+				ExpectError: regexp.MustCompile(`expected response to be one of \[.*\], got wrong_response`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWrongEndpoint,
+				ExpectError: regexp.MustCompile(`expected endpoint_type to be one of \[.*\], got wrong_endpoint`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWrongPriority,
+				ExpectError: regexp.MustCompile(`expected priority to be one of \[.*\], got wrong_priority`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigEmptyName,
+				ExpectError: regexp.MustCompile(`expected "name" to not be an empty string or whitespace`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigBlankName,
+				ExpectError: regexp.MustCompile(`expected "name" to not be an empty string or whitespace`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigEmptyQuery,
+				ExpectError: regexp.MustCompile(`expected "query" to not be an empty string`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithWrongDateFormat,
+				ExpectError: regexp.MustCompile(`(?s)date '2019-09-26T07:58:30\.996\+0200' does not match the required format.*'YYYY-MM-DD HH:MM:SS'`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithActivationDateBeforeNow,
+				ExpectError: regexp.MustCompile(`date '1970-01-01 00:00:00' must not be in the past`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithExpirationDateBeforeNow,
+				ExpectError: regexp.MustCompile(`date '1970-01-01 00:00:00' must not be in the past`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithExpirationDateBeforeActivationDate,
+				ExpectError: regexp.MustCompile(`expired_at date must be after activated_at date`),
+			},
+		},
+	})
+}
 
-  policy_options {
-    rate_limit {
-      applies_to               = "ip"
-      threshold                = 100
-      time_frame               = "1h"
-      response_after_threshold = "block"
-    }
-  }
+// TestAccCustomRuleResource_createAlreadyExists test the creation when a custom rule already exists with the same name
+func TestAccCustomRuleResource_createAlreadyExists(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+	mockClient.CreateFunc = func(ctx context.Context, params datadome.CustomRule) (*int, error) {
+		return nil, fmt.Errorf("The rule with name: 'acc-test' already exists")
+	}
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCustomRuleResourceConfig,
+				ExpectError: regexp.MustCompile(`'acc-test' already exists`),
+			},
+		},
+	})
 }
-`
 
-const testAccCustomRuleResourceConfigWithAllTrafficAndForbiddenTimeFrame = `
-provider "datadome" {}
+// TestAccCustomRuleResource_nameAlreadyUsed test the plan of a custom rule whose name is used by another custom rule
+func TestAccCustomRuleResource_nameAlreadyUsed(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+	_, err := mockClient.Create(context.Background(), datadome.CustomRule{
+		Name:     "acc-test",
+		Query:    "ip: 10.0.0.1",
+		Response: "allow",
+		Priority: "high",
+	})
+	if err != nil {
+		t.Fatalf("fail to create existing custom rule: %s", err)
+	}
 
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
 
-  policy_options {
-    rate_limit {
-      applies_to               = "all_traffic"
-      threshold                = 100
-      time_frame               = "15m"
-      response_after_threshold = "block"
-    }
-  }
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccCustomRuleResourceConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+				ExpectError:        regexp.MustCompile(`"acc-test" is already used by the custom rule`),
+			},
+		},
+	})
 }
-`
 
-const testAccCustomRuleResourceConfigWithIpAndForbiddenTimeFrame = `
-provider "datadome" {}
+// TestAccCustomRuleResource_updateAlreadyExists test the update when a custom rule already exists with the same name
+func TestAccCustomRuleResource_updateAlreadyExists(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+	mockClient.UpdateFunc = func(ctx context.Context, params datadome.CustomRule) (*datadome.CustomRule, error) {
+		return nil, fmt.Errorf("Another rule with name: 'acc-test-updated' already exists")
+	}
 
-resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "allow"
-  endpoint_type = "web"
-  priority      = "low"
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
 
-  policy_options {
-    rate_limit {
-      applies_to               = "ip"
-      threshold                = 100
-      time_frame               = "1d"
-      response_after_threshold = "block"
-    }
-  }
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test"),
+				),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigUpdate,
+				ExpectError: regexp.MustCompile(`'acc-test-updated' already exists`),
+			},
+		},
+	})
 }
-`
 
-const testAccCustomRuleResourceConfigWithPolicyOptionsOnBlock = `
+const testAccCustomRuleResourceConfigAdoptExisting = `
 provider "datadome" {}
 
 resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "block"
-  endpoint_type = "web"
-  priority      = "low"
-
-  policy_options {
-    rate_limit {
-      applies_to               = "ip"
-      threshold                = 100
-      time_frame               = "15m"
-      response_after_threshold = "block"
-    }
-  }
+  name           = "acc-test"
+  query          = "ip: 192.168.0.2"
+  response       = "block"
+  endpoint_type  = "web"
+  priority       = "low"
+  enabled        = true
+  adopt_existing = true
 }
 `
 
-const testAccCustomRuleResourceConfigWithMonetizeWithoutOverriddenBot = `
+// TestAccCustomRuleResource_adoptExisting test the creation of a custom rule when another rule already exists with the same name
+func TestAccCustomRuleResource_adoptExisting(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+	existingID := 1234
+	_, err := mockClient.Create(context.Background(), datadome.CustomRule{
+		ID:       &existingID,
+		Name:     "acc-test",
+		Query:    "ip: 192.168.0.1",
+		Response: "allow",
+		Priority: "high",
+	})
+	if err != nil {
+		t.Fatalf("fail to create existing custom rule: %s", err)
+	}
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfigAdoptExisting,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "id", "1234"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name", "acc-test"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", "ip: 192.168.0.2"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "response", "block"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "priority", "low"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "adopt_existing", "true"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCustomRuleResourceConfigNamePrefix = `
 provider "datadome" {}
 
 resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
+  name_prefix   = "acc-test-"
   query         = "ip: 192.168.0.1"
-  response      = "monetize"
+  response      = "block"
   endpoint_type = "web"
-  priority      = "low"
+
+  lifecycle {
+    create_before_destroy = true
+  }
 }
 `
 
-const testAccCustomRuleResourceConfigWithIntentBasedWithoutOverriddenBot = `
+const testAccCustomRuleResourceConfigNamePrefixBoth = `
 provider "datadome" {}
 
 resource "datadome_custom_rule" "accConfig" {
-  name          = "acc-test"
-  query         = "ip: 192.168.0.1"
-  response      = "intent_based"
-  endpoint_type = "web"
-  priority      = "low"
+  name        = "acc-test"
+  name_prefix = "acc-test-"
+  query       = "ip: 192.168.0.1"
+  response    = "block"
 }
 `
 
-// TestAccCustomRuleResource_wrongPolicyParameters tests validation errors for policy_options fields
-func TestAccCustomRuleResource_wrongPolicyParameters(t *testing.T) {
+// TestAccCustomRuleResource_namePrefix test the generation of a unique name from the name_prefix of a custom rule
+func TestAccCustomRuleResource_namePrefix(t *testing.T) {
 	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -1061,329 +1297,1516 @@ func TestAccCustomRuleResource_wrongPolicyParameters(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config:      testAccCustomRuleResourceConfigWithInvalidUUID,
-				ExpectError: regexp.MustCompile(`expected .* to be a valid UUID`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWithInvalidThreshold,
-				ExpectError: regexp.MustCompile(`expected .* to be at least \(1\)`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWithInvalidTimeFrame,
-				ExpectError: regexp.MustCompile(`expected .* to be one of \["1m" "15m" "1h" "4h" "1d"\]`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWithInvalidHour,
-				ExpectError: regexp.MustCompile(`expected .* to be in the range \(0 - 167\)`),
-			},
-			{
-				Config:      testAccCustomRuleResourceConfigWithBothPolicies,
-				ExpectError: regexp.MustCompile(`Invalid combination of arguments`),
+				Config:      testAccCustomRuleResourceConfigNamePrefixBoth,
+				ExpectError: regexp.MustCompile(`only one of ` + "`name,name_prefix`" + ` can be specified`),
 			},
 			{
-				Config:      testAccCustomRuleResourceConfigWithOverriddenBotAndNonAllTraffic,
-				ExpectError: regexp.MustCompile(`rate_limit\.applies_to must be "all_traffic" when overridden_bot is set`),
+				Config: testAccCustomRuleResourceConfigNamePrefix,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestMatchResourceAttr("datadome_custom_rule.accConfig", "name", regexp.MustCompile(`^acc-test-\d+$`)),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "name_prefix", "acc-test-"),
+				),
 			},
 			{
-				Config:      testAccCustomRuleResourceConfigWithAllTrafficAndForbiddenTimeFrame,
-				ExpectError: regexp.MustCompile(`rate_limit\.time_frame must be "1h" or "1d" when applies_to is "all_traffic"`),
+				Config:   testAccCustomRuleResourceConfigNamePrefix,
+				PlanOnly: true,
 			},
+		},
+	})
+}
+
+const testAccCustomRuleResourceConfigEndpointID = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "mobileLogin" {
+  name          = "acc-test-mobile-login"
+  source        = "Mobile App"
+  traffic_usage = "Login"
+  domain        = "example.com"
+}
+
+resource "datadome_custom_rule" "accConfig" {
+  name        = "acc-test"
+  query       = "ip: 192.168.0.1"
+  response    = "block"
+  endpoint_id = datadome_endpoint.mobileLogin.id
+}
+`
+
+const testAccCustomRuleResourceConfigEndpointIDChanged = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "mobileLogin" {
+  name          = "acc-test-mobile-login"
+  source        = "Mobile App"
+  traffic_usage = "General"
+  domain        = "example.com"
+}
+
+resource "datadome_custom_rule" "accConfig" {
+  name        = "acc-test"
+  query       = "ip: 192.168.0.2"
+  response    = "block"
+  endpoint_id = datadome_endpoint.mobileLogin.id
+}
+`
+
+const testAccCustomRuleResourceConfigEndpointIDContradiction = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "mobileLogin" {
+  name          = "acc-test-mobile-login"
+  source        = "Mobile App"
+  traffic_usage = "Login"
+  domain        = "example.com"
+}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "block"
+  endpoint_id   = datadome_endpoint.mobileLogin.id
+  endpoint_type = "web"
+}
+`
+
+// TestAccCustomRuleResource_endpointID test the endpoint type derived from the endpoint referenced by a custom rule
+func TestAccCustomRuleResource_endpointID(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+	mockClientEndpoint := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+			ClientEndpoint:   mockClientEndpoint,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
 			{
-				Config:      testAccCustomRuleResourceConfigWithIpAndForbiddenTimeFrame,
-				ExpectError: regexp.MustCompile(`rate_limit\.time_frame must be "1m", "15m", or "4h" when applies_to is "ip"`),
+				Config: testAccCustomRuleResourceConfigEndpointID,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttrPair("datadome_custom_rule.accConfig", "endpoint_id", "datadome_endpoint.mobileLogin", "id"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "endpoint_type", "api-app-mobile-login"),
+				),
 			},
 			{
-				Config:      testAccCustomRuleResourceConfigWithPolicyOptionsOnBlock,
-				ExpectError: regexp.MustCompile(`policy_options is only allowed when response is "allow" or "intent_based"`),
+				Config:   testAccCustomRuleResourceConfigEndpointID,
+				PlanOnly: true,
 			},
 			{
-				Config:      testAccCustomRuleResourceConfigWithMonetizeWithoutOverriddenBot,
-				ExpectError: regexp.MustCompile(`overridden_bot must be set when response is "monetize"`),
+				Config: testAccCustomRuleResourceConfigEndpointIDChanged,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "endpoint_type", "api-app-mobile"),
+				),
 			},
 			{
-				Config:      testAccCustomRuleResourceConfigWithIntentBasedWithoutOverriddenBot,
-				ExpectError: regexp.MustCompile(`overridden_bot must be set when response is "intent_based"`),
+				Config:      testAccCustomRuleResourceConfigEndpointIDContradiction,
+				ExpectError: regexp.MustCompile(`endpoint_type "web" contradicts the endpoint`),
 			},
 		},
 	})
 }
 
-/*
-Resources Endpoints tests
-*/
+// Config consts for overridden_bot and policy_options tests
 
-const testAccEndpointConfig = `
+const testAccCustomRuleResourceConfigWithOverriddenBot = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "simple" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "TFTEST"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  overridden_bot {
+    uuid = "550e8400-e29b-41d4-a716-446655440000"
+  }
 }
 `
 
-const testAccEndpointConfigWithQueryField = `
+const testAccCustomRuleResourceConfigWithRateLimit = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "simple" {
-  cookie_same_site   = "Lax"
-  description        = "This is a test"
-  detection_enabled  = false
-  name               = "test-terraform"
-  protection_enabled = false
-  response_format    = "auto"
-  source             = "Web Browser"
-  traffic_usage      = "Account Creation"
-  query              = "countrycode:FR"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    rate_limit {
+      applies_to               = "ip"
+      threshold                = 100
+      time_frame               = "15m"
+      response_after_threshold = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigUpdate = `
+const testAccCustomRuleResourceConfigWithTimeBox = `
 provider "datadome" {}
-resource "datadome_endpoint" "simple" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform-updated"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Mobile App"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "TFTEST"
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    time_box {
+      authorized_hours_of_the_week = [9, 10, 11, 12, 13, 14, 15, 16, 17]
+      response_outside_time_box    = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigWithRegex = `
+const testAccCustomRuleResourceConfigWithInvalidUUID = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "simple" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "(?:BLOCK|CHALLENGE)UA"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  overridden_bot {
+    uuid = "not-a-uuid"
+  }
 }
 `
 
-const testAccEndpointConfigWithoutOptionalFields = `
+const testAccCustomRuleResourceConfigWithInvalidThreshold = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "simple" {
-  name                 = "test-terraform"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "TFTEST"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    rate_limit {
+      applies_to               = "ip"
+      threshold                = 0
+      time_frame               = "15m"
+      response_after_threshold = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigWithPositionBefore = `
+const testAccCustomRuleResourceConfigWithInvalidTimeFrame = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "first" {
-  name                 = "test-terraform"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "TFTEST"
-}
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
 
-resource "datadome_endpoint" "second" {
-  name                 = "test-terraform"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "TFTEST"
-  position_before      = datadome_endpoint.first.id
+  policy_options {
+    rate_limit {
+      applies_to              = "ip"
+      threshold               = 100
+      time_frame              = "2h"
+      response_after_threshold = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigMissingFields = `
+const testAccCustomRuleResourceConfigWithInvalidHour = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "missing_fields" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    time_box {
+      authorized_hours_of_the_week = [168]
+      response_outside_time_box    = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigWrongResponseFormat = `
+const testAccCustomRuleResourceConfigWithHoursAndSchedule = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "wrong_response_format" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "wrong_response_format"
-  source               = "Web Browser"
-  user_agent_inclusion = "TFTEST"
-  traffic_usage        = "Account Creation"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    time_box {
+      authorized_hours_of_the_week = [9]
+      schedule {
+        days  = ["Mon"]
+        start = "09:00"
+        end   = "11:00"
+      }
+      response_outside_time_box = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigWrongSource = `
+const testAccCustomRuleResourceConfigWithEmptySchedule = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "wrong_source" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "wrong_source"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "TFTEST"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    time_box {
+      schedule {
+        days  = ["Mon"]
+        start = "09:00"
+        end   = "09:00"
+      }
+      response_outside_time_box = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigWrongTrafficUsage = `
+const testAccCustomRuleResourceConfigWithInvalidTimezone = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "wrong_traffic_usage" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Web Browser"
-  traffic_usage        = "wrong_traffic_usage"
-  user_agent_inclusion = "TFTEST"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    time_box {
+      schedule {
+        days     = ["Mon"]
+        start    = "09:00"
+        end      = "11:00"
+        timezone = "Mars/Olympus_Mons"
+      }
+      response_outside_time_box = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigWrongTrafficUsageWithSourceApi = `
+const testAccCustomRuleResourceConfigWithBothPolicies = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "wrong_traffic_usage" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Api"
-  traffic_usage        = "Rss"
-  user_agent_inclusion = "TFTEST"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    rate_limit {
+      applies_to               = "ip"
+      threshold                = 100
+      time_frame               = "15m"
+      response_after_threshold = "block"
+    }
+    time_box {
+      authorized_hours_of_the_week = [9, 10]
+      response_outside_time_box    = "block"
+    }
+  }
 }
 `
 
-const testAccEndpointConfigWrongTrafficUsageWithSourceMobileApp = `
+// TestAccCustomRuleResource_withOverriddenBot tests creation with a valid overridden_bot UUID and verifies both uuid and name are read back
+func TestAccCustomRuleResource_withOverriddenBot(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+
+	mockClient.ReadFunc = func(ctx context.Context, id int) (*datadome.CustomRule, error) {
+		return &datadome.CustomRule{
+			ID:           &id,
+			Name:         "acc-test",
+			Response:     "allow",
+			Query:        "ip: 192.168.0.1",
+			EndpointType: "web",
+			Priority:     "low",
+			OverriddenBot: &datadome.OverriddenBot{
+				UUID: "550e8400-e29b-41d4-a716-446655440000",
+				Name: "My Test Bot",
+			},
+		}, nil
+	}
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfigWithOverriddenBot,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "overridden_bot.0.uuid", "550e8400-e29b-41d4-a716-446655440000"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "overridden_bot.0.name", "My Test Bot"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccCustomRuleResource_withRateLimit tests creation with a rate_limit policy_options block
+func TestAccCustomRuleResource_withRateLimit(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfigWithRateLimit,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.rate_limit.0.applies_to", "ip"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.rate_limit.0.threshold", "100"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.rate_limit.0.time_frame", "15m"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.rate_limit.0.response_after_threshold", "block"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccCustomRuleResource_withTimeBox tests creation with a time_box policy_options block
+func TestAccCustomRuleResource_withTimeBox(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfigWithTimeBox,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.response_outside_time_box", "block"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.authorized_hours_of_the_week.#", "9"),
+					resource.TestCheckTypeSetElemAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.authorized_hours_of_the_week.*", "9"),
+				),
+			},
+		},
+	})
+}
+
+const testAccCustomRuleResourceConfigWithUnsortedTimeBox = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "wrong_traffic_usage" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Mobile App"
-  traffic_usage        = "Rss"
-  user_agent_inclusion = "TFTEST"
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    time_box {
+      authorized_hours_of_the_week = [17, 9, 10, 9]
+      response_outside_time_box    = "block"
+    }
+  }
+}
+`
+
+// TestAccCustomRuleResource_withUnsortedTimeBox test that the order and the duplicates of the authorized hours do not produce a diff
+func TestAccCustomRuleResource_withUnsortedTimeBox(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfigWithUnsortedTimeBox,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.authorized_hours_of_the_week.#", "3"),
+					testAccCheckCustomRuleAuthorizedHours(mockClient, []int{9, 10, 17}),
+				),
+			},
+			{
+				Config:   testAccCustomRuleResourceConfigWithUnsortedTimeBox,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccCustomRuleResourceConfigWithTimeBoxSchedule = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    time_box {
+      schedule {
+        days     = ["Mon", "Tue"]
+        start    = "09:00"
+        end      = "11:00"
+        timezone = "UTC"
+      }
+      schedule {
+        days  = ["Sun"]
+        start = "22:00"
+        end   = "02:00"
+      }
+      response_outside_time_box = "block"
+    }
+  }
+}
+`
+
+// testAccCheckCustomRuleAuthorizedHours checks the authorized hours sent to the API for the only custom rule of the mock client
+func testAccCheckCustomRuleAuthorizedHours(mockClient *datadome.MockClientCustomRule, expected []int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		customRules, err := mockClient.List(context.Background())
+		if err != nil {
+			return err
+		}
+		if len(customRules) != 1 {
+			return fmt.Errorf("expected 1 custom rule, got %d", len(customRules))
+		}
+		timeBox := customRules[0].PolicyOptions.TimeBox
+		if !slices.Equal(timeBox.AuthorizedHoursOfTheWeek, expected) {
+			return fmt.Errorf("expected authorized hours %v, got %v", expected, timeBox.AuthorizedHoursOfTheWeek)
+		}
+		return nil
+	}
+}
+
+func TestAccCustomRuleResource_withTimeBoxSchedule(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCustomRuleResourceConfigWithTimeBoxSchedule,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.schedule.#", "2"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.schedule.0.days.#", "2"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.schedule.1.timezone", "UTC"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "policy_options.0.time_box.0.authorized_hours_of_the_week.#", "0"),
+					testAccCheckCustomRuleAuthorizedHours(mockClient, []int{0, 1, 9, 10, 33, 34, 166, 167}),
+				),
+			},
+			{
+				Config:   testAccCustomRuleResourceConfigWithTimeBoxSchedule,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+const testAccCustomRuleResourceConfigWithOverriddenBotAndNonAllTraffic = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  overridden_bot {
+    uuid = "550e8400-e29b-41d4-a716-446655440000"
+  }
+
+  policy_options {
+    rate_limit {
+      applies_to               = "ip"
+      threshold                = 100
+      time_frame               = "1h"
+      response_after_threshold = "block"
+    }
+  }
+}
+`
+
+const testAccCustomRuleResourceConfigWithAllTrafficAndForbiddenTimeFrame = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    rate_limit {
+      applies_to               = "all_traffic"
+      threshold                = 100
+      time_frame               = "15m"
+      response_after_threshold = "block"
+    }
+  }
+}
+`
+
+const testAccCustomRuleResourceConfigWithIpAndForbiddenTimeFrame = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "allow"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    rate_limit {
+      applies_to               = "ip"
+      threshold                = 100
+      time_frame               = "1d"
+      response_after_threshold = "block"
+    }
+  }
+}
+`
+
+const testAccCustomRuleResourceConfigWithPolicyOptionsOnBlock = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "block"
+  endpoint_type = "web"
+  priority      = "low"
+
+  policy_options {
+    rate_limit {
+      applies_to               = "ip"
+      threshold                = 100
+      time_frame               = "15m"
+      response_after_threshold = "block"
+    }
+  }
+}
+`
+
+const testAccCustomRuleResourceConfigWithMonetizeWithoutOverriddenBot = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "monetize"
+  endpoint_type = "web"
+  priority      = "low"
+}
+`
+
+const testAccCustomRuleResourceConfigWithIntentBasedWithoutOverriddenBot = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "intent_based"
+  endpoint_type = "web"
+  priority      = "low"
+}
+`
+
+// TestAccCustomRuleResource_wrongPolicyParameters tests validation errors for policy_options fields
+func TestAccCustomRuleResource_wrongPolicyParameters(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCustomRuleResourceConfigWithInvalidUUID,
+				ExpectError: regexp.MustCompile(`expected .* to be a valid UUID`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithInvalidThreshold,
+				ExpectError: regexp.MustCompile(`expected .* to be at least \(1\)`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithInvalidTimeFrame,
+				ExpectError: regexp.MustCompile(`expected .* to be one of \["1m" "15m" "1h" "4h" "1d"\]`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithInvalidHour,
+				ExpectError: regexp.MustCompile(`expected .* to be in the range \(0 - 167\)`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithBothPolicies,
+				ExpectError: regexp.MustCompile(`Invalid combination of arguments`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithHoursAndSchedule,
+				ExpectError: regexp.MustCompile(`Invalid combination of arguments`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithEmptySchedule,
+				ExpectError: regexp.MustCompile(`the end time must differ from the start time`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithInvalidTimezone,
+				ExpectError: regexp.MustCompile(`to be a valid IANA time zone`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithOverriddenBotAndNonAllTraffic,
+				ExpectError: regexp.MustCompile(`rate_limit\.applies_to must be "all_traffic" when overridden_bot is set`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithAllTrafficAndForbiddenTimeFrame,
+				ExpectError: regexp.MustCompile(`rate_limit\.time_frame must be "1h" or "1d" when applies_to is "all_traffic"`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithIpAndForbiddenTimeFrame,
+				ExpectError: regexp.MustCompile(`rate_limit\.time_frame must be "1m", "15m", or "4h" when applies_to is "ip"`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithPolicyOptionsOnBlock,
+				ExpectError: regexp.MustCompile(`policy_options is only allowed when response is "allow" or "intent_based"`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithMonetizeWithoutOverriddenBot,
+				ExpectError: regexp.MustCompile(`overridden_bot must be set when response is "monetize"`),
+			},
+			{
+				Config:      testAccCustomRuleResourceConfigWithIntentBasedWithoutOverriddenBot,
+				ExpectError: regexp.MustCompile(`overridden_bot must be set when response is "intent_based"`),
+			},
+		},
+	})
+}
+
+/*
+Resources Endpoints tests
+*/
+
+const testAccEndpointConfig = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "simple" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWithQueryField = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "simple" {
+  cookie_same_site   = "Lax"
+  description        = "This is a test"
+  detection_enabled  = false
+  name               = "test-terraform"
+  protection_enabled = false
+  response_format    = "auto"
+  source             = "Web Browser"
+  traffic_usage      = "Account Creation"
+  query              = "countrycode:FR"
+}
+`
+
+const testAccEndpointConfigUpdate = `
+provider "datadome" {}
+resource "datadome_endpoint" "simple" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform-updated"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Mobile App"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWithRegex = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "simple" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "(?:BLOCK|CHALLENGE)UA"
+}
+`
+
+const testAccEndpointConfigWithoutOptionalFields = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "simple" {
+  name                 = "test-terraform"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWithPositionBefore = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "first" {
+  name                 = "test-terraform"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+}
+
+resource "datadome_endpoint" "second" {
+  name                 = "test-terraform"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+  position_before      = datadome_endpoint.first.id
+}
+`
+
+const testAccEndpointConfigMissingFields = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "missing_fields" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+}
+`
+
+const testAccEndpointConfigWrongResponseFormat = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "wrong_response_format" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "wrong_response_format"
+  source               = "Web Browser"
+  user_agent_inclusion = "TFTEST"
+  traffic_usage        = "Account Creation"
+}
+`
+
+const testAccEndpointConfigWrongSource = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "wrong_source" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "wrong_source"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWrongTrafficUsage = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "wrong_traffic_usage" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "wrong_traffic_usage"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWrongTrafficUsageWithSourceApi = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "wrong_traffic_usage" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Api"
+  traffic_usage        = "Rss"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWrongTrafficUsageWithSourceMobileApp = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "wrong_traffic_usage" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Mobile App"
+  traffic_usage        = "Rss"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWrongCookieSameSite = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "wrong_cookie_same_site" {
+  cookie_same_site     = "wrong_cookie_same_site"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWrongPositionBeforeFormat = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "wrong_cookie_same_site" {
+  cookie_same_site     = "Lax"
+  position_before      = "some_incorrect_id"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigInvalidRegex = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "wrong_cookie_same_site" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "wrong(.*"
+}
+`
+
+const testAccEndpointConfigWrongDetectionValue = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "simple" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = true
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "TFTEST"
+}
+`
+
+const testAccEndpointConfigWrongQuery = `
+provider "datadome" {}
+
+resource "datadome_endpoint" "simple" {
+  cookie_same_site     = "Lax"
+  description          = "This is a test"
+  detection_enabled    = false
+  name                 = "test-terraform"
+  protection_enabled   = false
+  response_format      = "auto"
+  source               = "Web Browser"
+  traffic_usage        = "Account Creation"
+  user_agent_inclusion = "should not be set"
+  query                = "countrycode:FR"
+}
+`
+
+// TestAccEndpointResource_basic tests the creation and the read of a new endpoint
+func TestAccEndpointResource_basic(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccEndpointResource_import tests the creation of an endpoint and its importation in another configuration
+func TestAccEndpointResource_import(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttrSet("datadome_endpoint.simple", "id"),
+				),
+			},
+			{
+				Config:            ``,
+				ResourceName:      "datadome_endpoint.simple",
+				ImportState:       true,
+				ImportStateIdFunc: testAccImportStateIdFunc("datadome_endpoint.simple"),
+				ImportStateVerify: true,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttrSet("datadome_endpoint.simple", "id"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccEndpointResource_importByName tests the importation of an endpoint by its name
+func TestAccEndpointResource_importByName(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttrSet("datadome_endpoint.simple", "id"),
+				),
+			},
+			{
+				Config:            ``,
+				ResourceName:      "datadome_endpoint.simple",
+				ImportState:       true,
+				ImportStateId:     "name:test-terraform",
+				ImportStateVerify: true,
+			},
+			{
+				Config:        ``,
+				ResourceName:  "datadome_endpoint.simple",
+				ImportState:   true,
+				ImportStateId: "name:unknown-endpoint",
+				ExpectError:   regexp.MustCompile(`no endpoint found with name "unknown-endpoint"`),
+			},
+		},
+	})
+}
+
+// TestAccEndpointResource_update test the creation of an endpoint and update it
+func TestAccEndpointResource_update(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+				),
+			},
+			{
+				Config: testAccEndpointConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform-updated"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Mobile App"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccEndpointResource_delete test the creation of a new endpoint and delete it
+func TestAccEndpointResource_delete(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttrSet("datadome_endpoint.simple", "id"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+				),
+			},
+			{
+				Config: `provider "datadome" {}`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceDoesNotExists("datadome_endpoint.simple"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccEndpointResource_createWithoutOptionalFields tests the creation of an endpoint resource without optional fields
+func TestAccEndpointResource_createWithoutOptionalFields(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfigWithoutOptionalFields,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "true"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccEndpointResource_createWithPositionBefore tests the creation of two resources
+// The first resource is created with the minimum required fields
+// The second resource is created with the minimum required fields and specify the `position_before` to use the ID of the first resource
+func TestAccEndpointResource_createWithPositionBefore(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfigWithPositionBefore,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.first"),
+					testAccCheckResourceExists("datadome_endpoint.second"),
+					resource.TestCheckResourceAttrSet("datadome_endpoint.first", "id"),
+					resource.TestCheckResourceAttrSet("datadome_endpoint.second", "id"),
+					resource.TestCheckResourceAttrSet("datadome_endpoint.second", "position_before"),
+					resource.TestCheckResourceAttrPair(
+						"datadome_endpoint.second", "position_before",
+						"datadome_endpoint.first", "id",
+					),
+				),
+			},
+		},
+	})
+}
+
+// TestAccEndpointResource_createWithRegex tests the creation of a new endpoint with a Regex format for the "user_agent_inclusion" field
+func TestAccEndpointResource_createWithRegex(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfigWithRegex,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "(?:BLOCK|CHALLENGE)UA"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccEndpointResource_createWithQueryField(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointConfigWithQueryField,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_endpoint.simple"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "query", "countrycode:FR"),
+				),
+			},
+		},
+	})
+}
+
+// TestAccEndpointResource_wrongParameters tests the creation of an endpoint resource by providing wrong inputs
+func TestAccEndpointResource_wrongParameters(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccEndpointConfigMissingFields,
+				ExpectError: regexp.MustCompile("Missing required argument"),
+			},
+			{
+				Config:      testAccEndpointConfigWrongResponseFormat,
+				ExpectError: regexp.MustCompile(`expected response_format to be one of \[.*\], got wrong_response_format`),
+			},
+			{
+				Config:      testAccEndpointConfigWrongSource,
+				ExpectError: regexp.MustCompile(`expected source to be one of \[.*\], got wrong_source`),
+			},
+			{
+				Config:      testAccEndpointConfigWrongTrafficUsage,
+				ExpectError: regexp.MustCompile(`expected traffic_usage to be one of \[.*\], got wrong_traffic_usage`),
+			},
+			{
+				Config:      testAccEndpointConfigWrongTrafficUsageWithSourceApi,
+				ExpectError: regexp.MustCompile(`expected "traffic_usage" to be one of {General}, got "Rss"`),
+			},
+			{
+				Config:      testAccEndpointConfigWrongTrafficUsageWithSourceMobileApp,
+				ExpectError: regexp.MustCompile(`expected "traffic_usage" to be one of {General, Login, Payment, Cart, Forms, Account Creation}, got "Rss"`),
+			},
+			{
+				Config:      testAccEndpointConfigWrongCookieSameSite,
+				ExpectError: regexp.MustCompile(`expected cookie_same_site to be one of \[.*\], got wrong_cookie_same_site`),
+			},
+			{
+				Config:      testAccEndpointConfigWrongPositionBeforeFormat,
+				ExpectError: regexp.MustCompile(`expected "position_before" to be a valid UUID, got some_incorrect_id`),
+			},
+			{
+				Config:      testAccEndpointConfigInvalidRegex,
+				ExpectError: regexp.MustCompile(`.*error parsing regexp.*`),
+			},
+			{
+				Config:      testAccEndpointConfigWrongDetectionValue,
+				ExpectError: regexp.MustCompile("the detection must be activated in order to activate the protection"),
+			},
+			{
+				Config:      testAccEndpointConfigWrongQuery,
+				ExpectError: regexp.MustCompile(`"query" must be empty if whether "domain", "path_inclusion", "path_exclusion", or "user_agent_inclusion" is filled`),
+			},
+		},
+	})
 }
-`
 
-const testAccEndpointConfigWrongCookieSameSite = `
+const testAccEndpointConfigAdoptExisting = `
 provider "datadome" {}
 
-resource "datadome_endpoint" "wrong_cookie_same_site" {
-  cookie_same_site     = "wrong_cookie_same_site"
-  description          = "This is a test"
-  detection_enabled    = false
+resource "datadome_endpoint" "simple" {
   name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
   source               = "Web Browser"
-  traffic_usage        = "Account Creation"
+  traffic_usage        = "Login"
   user_agent_inclusion = "TFTEST"
+  adopt_existing       = true
 }
 `
 
-const testAccEndpointConfigWrongPositionBeforeFormat = `
-provider "datadome" {}
-
-resource "datadome_endpoint" "wrong_cookie_same_site" {
-  cookie_same_site     = "Lax"
-  position_before      = "some_incorrect_id"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "TFTEST"
-}
-`
+// TestAccEndpointResource_nameAlreadyUsed tests the plan of an endpoint whose name is used by another endpoint
+func TestAccEndpointResource_nameAlreadyUsed(t *testing.T) {
+	mockClient := datadome.NewMockClientEndpoint()
+	_, err := mockClient.Create(context.Background(), datadome.Endpoint{
+		Name:         "test-terraform",
+		Source:       "Web Browser",
+		TrafficUsage: "General",
+	})
+	if err != nil {
+		t.Fatalf("fail to create existing endpoint: %s", err)
+	}
 
-const testAccEndpointConfigInvalidRegex = `
-provider "datadome" {}
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
 
-resource "datadome_endpoint" "wrong_cookie_same_site" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "wrong(.*"
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccEndpointConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+				ExpectError:        regexp.MustCompile(`"test-terraform" is already used by the endpoint`),
+			},
+		},
+	})
 }
-`
 
-const testAccEndpointConfigWrongDetectionValue = `
+const testAccEndpointConfigTestCases = `
 provider "datadome" {}
 
 resource "datadome_endpoint" "simple" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = true
-  response_format      = "auto"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "TFTEST"
+  name           = "test-terraform"
+  source         = "Web Browser"
+  traffic_usage  = "Login"
+  domain         = "example\\.com$"
+  path_inclusion = "^/login"
+
+  test_case {
+    url = "https://www.example.com/login"
+  }
+
+  test_case {
+    url          = "https://www.example.com/home"
+    expect_match = false
+  }
 }
 `
 
-const testAccEndpointConfigWrongQuery = `
+const testAccEndpointConfigFailingTestCases = `
 provider "datadome" {}
 
 resource "datadome_endpoint" "simple" {
-  cookie_same_site     = "Lax"
-  description          = "This is a test"
-  detection_enabled    = false
-  name                 = "test-terraform"
-  protection_enabled   = false
-  response_format      = "auto"
-  source               = "Web Browser"
-  traffic_usage        = "Account Creation"
-  user_agent_inclusion = "should not be set"
-  query                = "countrycode:FR"
+  name           = "test-terraform"
+  source         = "Web Browser"
+  traffic_usage  = "Login"
+  domain         = "example\\.com$"
+  path_inclusion = "^/signin"
+
+  test_case {
+    url = "https://www.example.com/login"
+  }
+
+  test_case {
+    url          = "https://www.example.com/home"
+    expect_match = false
+  }
 }
 `
 
-// TestAccEndpointResource_basic tests the creation and the read of a new endpoint
-func TestAccEndpointResource_basic(t *testing.T) {
+// TestAccEndpointResource_testCases tests the evaluation of the test cases of an endpoint during the plan
+func TestAccEndpointResource_testCases(t *testing.T) {
 	mockClient := datadome.NewMockClientEndpoint()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -1397,27 +2820,33 @@ func TestAccEndpointResource_basic(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccEndpointConfig,
+				Config: testAccEndpointConfigTestCases,
 				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "test_case.#", "2"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "test_case.1.expect_match", "false"),
 				),
 			},
+			{
+				Config:      testAccEndpointConfigFailingTestCases,
+				ExpectError: regexp.MustCompile(`1 of 2 test cases failed:\s*- https://www.example.com/login: expected to match the endpoint, but it does not`),
+			},
 		},
 	})
 }
 
-// TestAccEndpointResource_import tests the creation of an endpoint and its importation in another configuration
-func TestAccEndpointResource_import(t *testing.T) {
+// TestAccEndpointResource_adoptExisting tests the creation of an endpoint when another endpoint already exists with the same name
+func TestAccEndpointResource_adoptExisting(t *testing.T) {
 	mockClient := datadome.NewMockClientEndpoint()
+	existingID := "9b2a3a5e-6a55-4c5b-9a8e-3f1d2c4b5a6e"
+	_, err := mockClient.Create(context.Background(), datadome.Endpoint{
+		ID:           &existingID,
+		Name:         "test-terraform",
+		Source:       "Web Browser",
+		TrafficUsage: "General",
+	})
+	if err != nil {
+		t.Fatalf("fail to create existing endpoint: %s", err)
+	}
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
@@ -1430,43 +2859,273 @@ func TestAccEndpointResource_import(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccEndpointConfig,
+				Config: testAccEndpointConfigAdoptExisting,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttrSet("datadome_endpoint.simple", "id"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "id", existingID),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Login"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+					resource.TestCheckResourceAttr("datadome_endpoint.simple", "adopt_existing", "true"),
+				),
+			},
+		},
+	})
+}
+
+const testAccVerifiedBotsDataSourceConfig = `
+provider "datadome" {}
+
+data "datadome_verified_bots" "all" {}
+
+data "datadome_verified_bots" "byName" {
+  name = "googlebot"
+}
+
+data "datadome_verified_bots" "byCategory" {
+  category = "AI Agent"
+}
+`
+
+// TestAccVerifiedBotsDataSource test the listing of verified bots filtered by name and category
+func TestAccVerifiedBotsDataSource(t *testing.T) {
+	mockClient := datadome.NewMockClientVerifiedBot(
+		datadome.VerifiedBot{UUID: "550e8400-e29b-41d4-a716-446655440000", Name: "Googlebot", Category: "Search Engine"},
+		datadome.VerifiedBot{UUID: "6ba7b810-9dad-11d1-80b4-00c04fd430c8", Name: "ChatGPT-User", Category: "AI Agent"},
+		datadome.VerifiedBot{UUID: "6ba7b811-9dad-11d1-80b4-00c04fd430c8", Name: "Claude-User", Category: "AI Agent"},
+	)
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientVerifiedBot: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVerifiedBotsDataSourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.datadome_verified_bots.all", "verified_bots.#", "3"),
+					resource.TestCheckResourceAttr("data.datadome_verified_bots.byName", "verified_bots.#", "1"),
+					resource.TestCheckResourceAttr("data.datadome_verified_bots.byName", "verified_bots.0.uuid", "550e8400-e29b-41d4-a716-446655440000"),
+					resource.TestCheckResourceAttr("data.datadome_verified_bots.byName", "verified_bots.0.name", "Googlebot"),
+					resource.TestCheckResourceAttr("data.datadome_verified_bots.byName", "verified_bots.0.category", "Search Engine"),
+					resource.TestCheckResourceAttr("data.datadome_verified_bots.byCategory", "verified_bots.#", "2"),
+					resource.TestCheckResourceAttr("data.datadome_verified_bots.byCategory", "verified_bots.1.name", "Claude-User"),
 				),
 			},
+		},
+	})
+}
+
+const testAccCustomRuleEvaluationDataSourceConfig = `
+provider "datadome" {}
+
+data "datadome_custom_rule_evaluation" "rules" {
+  custom_rule {
+    name     = "allow-office"
+    query    = "ip:10.0.0.0/8"
+    response = "allow"
+    priority = "low"
+  }
+
+  custom_rule {
+    name          = "block-curl-login"
+    query         = "headers.user-agent:*curl* AND countrycode:FR"
+    response      = "block"
+    endpoint_type = "login"
+  }
+
+  request {
+    endpoint_type = "login"
+    ip            = "10.1.2.3"
+    country_code  = "FR"
+    headers = {
+      "User-Agent" = "curl/8.0"
+    }
+  }
+
+  request {
+    endpoint_type = "web"
+    ip            = "10.1.2.3"
+    country_code  = "FR"
+    headers = {
+      "User-Agent" = "curl/8.0"
+    }
+  }
+
+  request {
+    endpoint_type = "web"
+    ip            = "192.168.0.1"
+  }
+}
+`
+
+// TestAccCustomRuleEvaluationDataSource test the custom rules matched by sample requests, following priority and endpoint_type
+func TestAccCustomRuleEvaluationDataSource(t *testing.T) {
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientCustomRule: datadome.NewMockClientCustomRule(),
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
 			{
-				Config:            ``,
-				ResourceName:      "datadome_endpoint.simple",
-				ImportState:       true,
-				ImportStateIdFunc: testAccImportStateIdFunc("datadome_endpoint.simple"),
-				ImportStateVerify: true,
+				Config: testAccCustomRuleEvaluationDataSourceConfig,
 				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttrSet("datadome_endpoint.simple", "id"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+					resource.TestCheckResourceAttr("data.datadome_custom_rule_evaluation.rules", "result.#", "3"),
+					resource.TestCheckResourceAttr("data.datadome_custom_rule_evaluation.rules", "result.0.custom_rule_name", "block-curl-login"),
+					resource.TestCheckResourceAttr("data.datadome_custom_rule_evaluation.rules", "result.0.response", "block"),
+					resource.TestCheckResourceAttr("data.datadome_custom_rule_evaluation.rules", "result.1.custom_rule_name", "allow-office"),
+					resource.TestCheckResourceAttr("data.datadome_custom_rule_evaluation.rules", "result.1.response", "allow"),
+					resource.TestCheckResourceAttr("data.datadome_custom_rule_evaluation.rules", "result.2.matched", "false"),
+				),
+			},
+		},
+	})
+}
+
+const testAccEndpointMatchDataSourceConfig = `
+provider "datadome" {}
+
+data "datadome_endpoint_match" "routing" {
+  request {
+    host = "www.example.com"
+    path = "/login"
+  }
+
+  request {
+    host       = "api.example.com"
+    path       = "/login"
+    user_agent = "MyApp/1.0"
+  }
+
+  request {
+    host = "www.example.com"
+    path = "/products"
+  }
+
+  request {
+    host = "example.org"
+  }
+}
+`
+
+// TestAccEndpointMatchDataSource test the endpoints matched by sample requests, following position_before
+func TestAccEndpointMatchDataSource(t *testing.T) {
+	ptr := func(value string) *string { return &value }
+	mockClient := datadome.NewMockClientEndpoint()
+	for _, endpoint := range []datadome.Endpoint{
+		{ID: ptr("api"), Name: "api", PositionBefore: ptr("web"), Domain: ptr(`^api\.example\.com$`), UserAgentInclusion: ptr("MyApp/")},
+		{ID: ptr("login"), Name: "login", PositionBefore: ptr("web"), Domain: ptr(`example\.com$`), PathInclusion: ptr("^/login")},
+		{ID: ptr("web"), Name: "web", Domain: ptr(`example\.com$`)},
+	} {
+		if _, err := mockClient.Create(context.Background(), endpoint); err != nil {
+			t.Fatalf("fail to create endpoint: %s", err)
+		}
+	}
+
+	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		return &ProviderConfig{
+			ClientEndpoint: mockClient,
+		}, nil
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEndpointMatchDataSourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.datadome_endpoint_match.routing", "result.#", "4"),
+					resource.TestCheckResourceAttr("data.datadome_endpoint_match.routing", "result.0.endpoint_name", "login"),
+					resource.TestCheckResourceAttr("data.datadome_endpoint_match.routing", "result.1.endpoint_id", "api"),
+					resource.TestCheckResourceAttr("data.datadome_endpoint_match.routing", "result.2.endpoint_name", "web"),
+					resource.TestCheckResourceAttr("data.datadome_endpoint_match.routing", "result.3.matched", "false"),
+					resource.TestCheckResourceAttr("data.datadome_endpoint_match.routing", "result.3.endpoint_id", ""),
 				),
 			},
 		},
 	})
 }
 
-// TestAccEndpointResource_update test the creation of an endpoint and update it
-func TestAccEndpointResource_update(t *testing.T) {
-	mockClient := datadome.NewMockClientEndpoint()
+const testAccCustomRuleResourceConfigWithMatch = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  response      = "block"
+  endpoint_type = "web"
+  priority      = "low"
+
+  match {
+    field    = "ip"
+    operator = "equals"
+    values   = ["192.168.0.0/24", "10.0.0.1"]
+  }
+
+  match {
+    any {
+      field    = "user-agent"
+      operator = "contains"
+      values   = ["curl", "python requests"]
+    }
+    any {
+      field    = "url"
+      operator = "starts_with"
+      values   = ["/api/"]
+    }
+  }
+}
+`
+
+const testAccCustomRuleResourceConfigWithMatchAndQuery = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  query         = "ip: 192.168.0.1"
+  response      = "block"
+  endpoint_type = "web"
+  priority      = "low"
+
+  match {
+    field    = "ip"
+    operator = "equals"
+    values   = ["192.168.0.1"]
+  }
+}
+`
+
+const testAccCustomRuleResourceConfigWithIncompleteMatch = `
+provider "datadome" {}
+
+resource "datadome_custom_rule" "accConfig" {
+  name          = "acc-test"
+  response      = "block"
+  endpoint_type = "web"
+  priority      = "low"
+
+  match {
+    field  = "ip"
+    values = ["192.168.0.1"]
+  }
+}
+`
+
+// TestAccCustomRuleResource_withMatch test that match blocks are compiled into the query of the custom rule
+func TestAccCustomRuleResource_withMatch(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
-			ClientEndpoint: mockClient,
+			ClientCustomRule: mockClient,
 		}, nil
 	}
 
@@ -1475,39 +3134,36 @@ func TestAccEndpointResource_update(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccEndpointConfig,
+				Config: testAccCustomRuleResourceConfigWithMatch,
 				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+					testAccCheckResourceExists("datadome_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", `ip:(192.168.0.0/24 OR 10.0.0.1) AND (user-agent:(*curl* OR *python\ requests*) OR url:/api/*)`),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "match.#", "2"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "match.1.any.#", "2"),
 				),
 			},
 			{
-				Config: testAccEndpointConfigUpdate,
+				Config:   testAccCustomRuleResourceConfigWithMatch,
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCustomRuleResourceConfig,
 				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform-updated"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Mobile App"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "query", "ip: 192.168.0.1"),
+					resource.TestCheckResourceAttr("datadome_custom_rule.accConfig", "match.#", "0"),
 				),
 			},
 		},
 	})
 }
 
-// TestAccEndpointResource_delete test the creation of a new endpoint and delete it
-func TestAccEndpointResource_delete(t *testing.T) {
-	mockClient := datadome.NewMockClientEndpoint()
+// TestAccCustomRuleResource_wrongMatchParameters test the validation of match blocks
+func TestAccCustomRuleResource_wrongMatchParameters(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
-			ClientEndpoint: mockClient,
+			ClientCustomRule: mockClient,
 		}, nil
 	}
 
@@ -1516,38 +3172,73 @@ func TestAccEndpointResource_delete(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccEndpointConfig,
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttrSet("datadome_endpoint.simple", "id"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
-				),
+				Config:      testAccCustomRuleResourceConfigWithMatchAndQuery,
+				ExpectError: regexp.MustCompile(`Invalid combination of arguments`),
 			},
 			{
-				Config: `provider "datadome" {}`,
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceDoesNotExists("datadome_endpoint.simple"),
-				),
+				Config:      testAccCustomRuleResourceConfigWithIncompleteMatch,
+				ExpectError: regexp.MustCompile(`match\.0: field, operator and values are required by a match condition`),
 			},
 		},
 	})
 }
 
-// TestAccEndpointResource_createWithoutOptionalFields tests the creation of an endpoint resource without optional fields
-func TestAccEndpointResource_createWithoutOptionalFields(t *testing.T) {
-	mockClient := datadome.NewMockClientEndpoint()
+/*
+Resources IPRuleSet tests
+*/
+
+const testAccIPRuleSetResourceConfig = `
+resource "datadome_ip_rule_set" "accConfig" {
+	name             = "acc-test-blocklist"
+	ips              = ["10.0.0.0", "10.0.0.1", "10.0.0.5", "192.168.1.0/24", "192.168.0.0/24", "172.16.0.1", "10.0.1.1"]
+	response         = "block"
+	endpoint_type    = "web"
+	max_query_length = 64
+}
+`
+
+const testAccIPRuleSetResourceConfigUpdate = `
+resource "datadome_ip_rule_set" "accConfig" {
+	name             = "acc-test-blocklist"
+	ips              = ["10.0.0.0", "10.0.0.1", "10.0.0.5", "192.168.1.0/24", "192.168.0.0/24", "172.16.0.1", "10.0.1.2"]
+	response         = "block"
+	endpoint_type    = "web"
+	max_query_length = 64
+}
+`
+
+const testAccIPRuleSetResourceConfigWrongIP = `
+resource "datadome_ip_rule_set" "accConfig" {
+	name     = "acc-test-blocklist"
+	ips      = ["10.0.0.256"]
+	response = "block"
+}
+`
+
+// testAccCheckCustomRuleQuery checks the query of the custom rule stored by the mock client with the given name
+func testAccCheckCustomRuleQuery(mockClient *datadome.MockClientCustomRule, name, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		customRule, err := findCustomRuleByName(context.Background(), mockClient, name)
+		if err != nil {
+			return err
+		}
+		if customRule == nil {
+			return fmt.Errorf("custom rule %q not found", name)
+		}
+		if customRule.Query != expected {
+			return fmt.Errorf("expected the query of custom rule %q to be %q, got %q", name, expected, customRule.Query)
+		}
+		return nil
+	}
+}
+
+// TestAccIPRuleSetResource_basic test the sharding of an IP rule set across custom rules
+func TestAccIPRuleSetResource_basic(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
-			ClientEndpoint: mockClient,
+			ClientCustomRule: mockClient,
 		}, nil
 	}
 
@@ -1556,32 +3247,58 @@ func TestAccEndpointResource_createWithoutOptionalFields(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccEndpointConfigWithoutOptionalFields,
+				Config: testAccIPRuleSetResourceConfig,
 				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "true"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "TFTEST"),
+					testAccCheckResourceExists("datadome_ip_rule_set.accConfig"),
+					resource.TestCheckResourceAttr("datadome_ip_rule_set.accConfig", "cidrs.#", "5"),
+					resource.TestCheckTypeSetElemAttr("datadome_ip_rule_set.accConfig", "cidrs.*", "10.0.0.0/31"),
+					resource.TestCheckTypeSetElemAttr("datadome_ip_rule_set.accConfig", "cidrs.*", "192.168.0.0/23"),
+					resource.TestCheckResourceAttr("datadome_ip_rule_set.accConfig", "shard.#", "2"),
+					resource.TestCheckResourceAttr("datadome_ip_rule_set.accConfig", "shard.0.name", "acc-test-blocklist-1"),
+					testAccCheckCustomRuleCount(mockClient, 2),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-blocklist-1", "ip:(10.0.0.0/31 OR 10.0.0.5 OR 10.0.1.1 OR 172.16.0.1)"),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-blocklist-2", "ip:192.168.0.0/23"),
+				),
+			},
+			{
+				Config:   testAccIPRuleSetResourceConfig,
+				PlanOnly: true,
+			},
+			{
+				Config: testAccIPRuleSetResourceConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("datadome_ip_rule_set.accConfig", "shard.#", "2"),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-blocklist-1", "ip:(10.0.0.0/31 OR 10.0.0.5 OR 10.0.1.2 OR 172.16.0.1)"),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-blocklist-2", "ip:192.168.0.0/23"),
 				),
 			},
+			{
+				ResourceName:            "datadome_ip_rule_set.accConfig",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"ips", "max_query_length"},
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					// the IP addresses are rebuilt from the aggregated ranges of the shards
+					if ips := states[0].Attributes["ips.#"]; ips != "5" {
+						return fmt.Errorf("expected the 5 aggregated ranges in ips, got %s", ips)
+					}
+					return nil
+				},
+			},
 		},
 	})
 }
 
-// TestAccEndpointResource_createWithPositionBefore tests the creation of two resources
-// The first resource is created with the minimum required fields
-// The second resource is created with the minimum required fields and specify the `position_before` to use the ID of the first resource
-func TestAccEndpointResource_createWithPositionBefore(t *testing.T) {
-	mockClient := datadome.NewMockClientEndpoint()
+// TestAccIPRuleSetResource_shardNameTaken test that the plan fails when a custom rule is named like a shard of the set
+func TestAccIPRuleSetResource_shardNameTaken(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
+	if _, err := mockClient.Create(context.Background(), datadome.CustomRule{Name: "acc-test-blocklist-2", Query: "ip:10.0.0.1", Response: "block"}); err != nil {
+		t.Fatalf("fail to create custom rule: %s", err)
+	}
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
-			ClientEndpoint: mockClient,
+			ClientCustomRule: mockClient,
 		}, nil
 	}
 
@@ -1590,62 +3307,93 @@ func TestAccEndpointResource_createWithPositionBefore(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccEndpointConfigWithPositionBefore,
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.first"),
-					testAccCheckResourceExists("datadome_endpoint.second"),
-					resource.TestCheckResourceAttrSet("datadome_endpoint.first", "id"),
-					resource.TestCheckResourceAttrSet("datadome_endpoint.second", "id"),
-					resource.TestCheckResourceAttrSet("datadome_endpoint.second", "position_before"),
-					resource.TestCheckResourceAttrPair(
-						"datadome_endpoint.second", "position_before",
-						"datadome_endpoint.first", "id",
-					),
-				),
+				Config:      testAccIPRuleSetResourceConfig,
+				ExpectError: regexp.MustCompile(`the shards of "acc-test-blocklist" would be named like the custom rule "acc-test-blocklist-2"`),
 			},
 		},
 	})
 }
 
-// TestAccEndpointResource_createWithRegex tests the creation of a new endpoint with a Regex format for the "user_agent_inclusion" field
-func TestAccEndpointResource_createWithRegex(t *testing.T) {
-	mockClient := datadome.NewMockClientEndpoint()
-
-	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-		return &ProviderConfig{
-			ClientEndpoint: mockClient,
-		}, nil
-	}
-
+// TestAccIPRuleSetResource_wrongIP test the validation of the IP addresses
+func TestAccIPRuleSetResource_wrongIP(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:          func() { testAccResourcePreCheck(t) },
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccEndpointConfigWithRegex,
-				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "user_agent_inclusion", "(?:BLOCK|CHALLENGE)UA"),
-				),
+				Config:      testAccIPRuleSetResourceConfigWrongIP,
+				ExpectError: regexp.MustCompile(`to be an IP address or a CIDR range`),
 			},
 		},
 	})
 }
 
-func TestAccEndpointResource_createWithQueryField(t *testing.T) {
-	mockClient := datadome.NewMockClientEndpoint()
+/*
+Resources CustomRuleSet tests
+*/
+
+const testAccCustomRuleSetResourceConfig = `
+resource "datadome_custom_rule_set" "accConfig" {
+	name = "acc-test-set"
+
+	rule {
+		name     = "acc-test-set-allow"
+		query    = "ip:1.1.1.1"
+		response = "allow"
+	}
+
+	rule {
+		name          = "acc-test-set-block"
+		query         = "ip:2.2.2.2"
+		response      = "block"
+		endpoint_type = "web"
+	}
+}
+`
+
+const testAccCustomRuleSetResourceConfigUpdate = `
+resource "datadome_custom_rule_set" "accConfig" {
+	name = "acc-test-set"
+
+	rule {
+		name     = "acc-test-set-allow"
+		query    = "ip:1.1.1.2"
+		response = "allow"
+	}
+
+	rule {
+		name     = "acc-test-set-captcha"
+		query    = "ip:3.3.3.3"
+		response = "captcha"
+	}
+}
+`
+
+const testAccCustomRuleSetResourceConfigDuplicate = `
+resource "datadome_custom_rule_set" "accConfig" {
+	name = "acc-test-set"
+
+	rule {
+		name     = "acc-test-set-allow"
+		query    = "ip:1.1.1.1"
+		response = "allow"
+	}
+
+	rule {
+		name     = "acc-test-set-allow"
+		query    = "ip:2.2.2.2"
+		response = "block"
+	}
+}
+`
+
+// TestAccCustomRuleSetResource_basic test the reconciliation of the custom rules of a set
+func TestAccCustomRuleSetResource_basic(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
-			ClientEndpoint: mockClient,
+			ClientCustomRule: mockClient,
 		}, nil
 	}
 
@@ -1654,31 +3402,77 @@ func TestAccEndpointResource_createWithQueryField(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccEndpointConfigWithQueryField,
+				Config: testAccCustomRuleSetResourceConfig,
 				Check: resource.ComposeTestCheckFunc(
-					testAccCheckResourceExists("datadome_endpoint.simple"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "cookie_same_site", "Lax"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "description", "This is a test"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "detection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "name", "test-terraform"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "protection_enabled", "false"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "response_format", "auto"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "source", "Web Browser"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "traffic_usage", "Account Creation"),
-					resource.TestCheckResourceAttr("datadome_endpoint.simple", "query", "countrycode:FR"),
+					testAccCheckResourceExists("datadome_custom_rule_set.accConfig"),
+					resource.TestCheckResourceAttr("datadome_custom_rule_set.accConfig", "rule.#", "2"),
+					resource.TestCheckResourceAttr("datadome_custom_rule_set.accConfig", "rule_ids.%", "2"),
+					testAccCheckCustomRuleCount(mockClient, 2),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-set-allow", "ip:1.1.1.1"),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-set-block", "ip:2.2.2.2"),
+				),
+			},
+			{
+				Config:   testAccCustomRuleSetResourceConfig,
+				PlanOnly: true,
+			},
+			{
+				Config: testAccCustomRuleSetResourceConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("datadome_custom_rule_set.accConfig", "rule_ids.%", "2"),
+					resource.TestCheckResourceAttrSet("datadome_custom_rule_set.accConfig", "rule_ids.acc-test-set-captcha"),
+					testAccCheckCustomRuleCount(mockClient, 2),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-set-allow", "ip:1.1.1.2"),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-set-captcha", "ip:3.3.3.3"),
 				),
 			},
 		},
 	})
 }
 
-// TestAccEndpointResource_wrongParameters tests the creation of an endpoint resource by providing wrong inputs
-func TestAccEndpointResource_wrongParameters(t *testing.T) {
-	mockClient := datadome.NewMockClientEndpoint()
+// TestAccCustomRuleSetResource_duplicateName test the rejection of rules sharing the same name
+func TestAccCustomRuleSetResource_duplicateName(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:          func() { testAccResourcePreCheck(t) },
+		ProviderFactories: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccCustomRuleSetResourceConfigDuplicate,
+				ExpectError: regexp.MustCompile(`is already used by rule.0`),
+			},
+		},
+	})
+}
+
+/*
+Resources MultiEndpointCustomRule tests
+*/
+
+const testAccMultiEndpointCustomRuleResourceConfig = `
+resource "datadome_multi_endpoint_custom_rule" "accConfig" {
+	name           = "acc-test-multi"
+	endpoint_types = ["web", "api-app-mobile", "agentic-general"]
+	query          = "ip:1.1.1.1"
+	response       = "block"
+}
+`
+
+const testAccMultiEndpointCustomRuleResourceConfigUpdate = `
+resource "datadome_multi_endpoint_custom_rule" "accConfig" {
+	name           = "acc-test-multi"
+	endpoint_types = ["web", "api-app-mobile", "agentic-login"]
+	query          = "ip:2.2.2.2"
+	response       = "block"
+}
+`
+
+// TestAccMultiEndpointCustomRuleResource_basic test the custom rules created for each endpoint type
+func TestAccMultiEndpointCustomRuleResource_basic(t *testing.T) {
+	mockClient := datadome.NewMockClientCustomRule()
 
 	testAccProvider.ConfigureContextFunc = func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
 		return &ProviderConfig{
-			ClientEndpoint: mockClient,
+			ClientCustomRule: mockClient,
 		}, nil
 	}
 
@@ -1687,48 +3481,43 @@ func TestAccEndpointResource_wrongParameters(t *testing.T) {
 		ProviderFactories: testAccProviders,
 		Steps: []resource.TestStep{
 			{
-				Config:      testAccEndpointConfigMissingFields,
-				ExpectError: regexp.MustCompile("Missing required argument"),
-			},
-			{
-				Config:      testAccEndpointConfigWrongResponseFormat,
-				ExpectError: regexp.MustCompile(`expected response_format to be one of \[.*\], got wrong_response_format`),
-			},
-			{
-				Config:      testAccEndpointConfigWrongSource,
-				ExpectError: regexp.MustCompile(`expected source to be one of \[.*\], got wrong_source`),
-			},
-			{
-				Config:      testAccEndpointConfigWrongTrafficUsage,
-				ExpectError: regexp.MustCompile(`expected traffic_usage to be one of \[.*\], got wrong_traffic_usage`),
-			},
-			{
-				Config:      testAccEndpointConfigWrongTrafficUsageWithSourceApi,
-				ExpectError: regexp.MustCompile(`expected "traffic_usage" to be one of {General}, got "Rss"`),
-			},
-			{
-				Config:      testAccEndpointConfigWrongTrafficUsageWithSourceMobileApp,
-				ExpectError: regexp.MustCompile(`expected "traffic_usage" to be one of {General, Login, Payment, Cart, Forms, Account Creation}, got "Rss"`),
-			},
-			{
-				Config:      testAccEndpointConfigWrongCookieSameSite,
-				ExpectError: regexp.MustCompile(`expected cookie_same_site to be one of \[.*\], got wrong_cookie_same_site`),
+				Config: testAccMultiEndpointCustomRuleResourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourceExists("datadome_multi_endpoint_custom_rule.accConfig"),
+					resource.TestCheckResourceAttr("datadome_multi_endpoint_custom_rule.accConfig", "rule_ids.%", "3"),
+					testAccCheckCustomRuleCount(mockClient, 3),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-multi-web", "ip:1.1.1.1"),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-multi-api-app-mobile", "ip:1.1.1.1"),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-multi-agentic-general", "ip:1.1.1.1"),
+				),
 			},
 			{
-				Config:      testAccEndpointConfigWrongPositionBeforeFormat,
-				ExpectError: regexp.MustCompile(`expected "position_before" to be a valid UUID, got some_incorrect_id`),
+				Config:   testAccMultiEndpointCustomRuleResourceConfig,
+				PlanOnly: true,
 			},
 			{
-				Config:      testAccEndpointConfigInvalidRegex,
-				ExpectError: regexp.MustCompile(`.*error parsing regexp.*`),
+				Config: testAccMultiEndpointCustomRuleResourceConfigUpdate,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("datadome_multi_endpoint_custom_rule.accConfig", "rule_ids.agentic-login"),
+					resource.TestCheckNoResourceAttr("datadome_multi_endpoint_custom_rule.accConfig", "rule_ids.agentic-general"),
+					testAccCheckCustomRuleCount(mockClient, 3),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-multi-web", "ip:2.2.2.2"),
+					testAccCheckCustomRuleQuery(mockClient, "acc-test-multi-agentic-login", "ip:2.2.2.2"),
+				),
 			},
 			{
-				Config:      testAccEndpointConfigWrongDetectionValue,
-				ExpectError: regexp.MustCompile("the detection must be activated in order to activate the protection"),
+				Config:            testAccMultiEndpointCustomRuleResourceConfigUpdate,
+				ResourceName:      "datadome_multi_endpoint_custom_rule.accConfig",
+				ImportState:       true,
+				ImportStateId:     "acc-test-multi",
+				ImportStateVerify: true,
 			},
 			{
-				Config:      testAccEndpointConfigWrongQuery,
-				ExpectError: regexp.MustCompile(`"query" must be empty if whether "domain", "path_inclusion", "path_exclusion", or "user_agent_inclusion" is filled`),
+				Config:        testAccMultiEndpointCustomRuleResourceConfigUpdate,
+				ResourceName:  "datadome_multi_endpoint_custom_rule.accConfig",
+				ImportState:   true,
+				ImportStateId: "unknown-rule",
+				ExpectError:   regexp.MustCompile(`no custom rule named "unknown-rule-<endpoint_type>" found`),
 			},
 		},
 	})
@@ -3,17 +3,53 @@ package datadome
 import (
 	"context"
 	"fmt"
+	"log"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/datadome/terraform-provider/common"
 	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+// customRuleResponses lists the responses of a custom rule
+var customRuleResponses = []string{"allow", "captcha", "block", "device_check", "intent_based", "monetize"}
+
+// customRulePriorities lists the priorities of a custom rule
+var customRulePriorities = []string{"high", "normal", "low"}
+
+// customRuleEndpointTypes lists the endpoint types a custom rule applies to
+var customRuleEndpointTypes = []string{
+	"web",
+	"account-creation",
+	"login",
+	"cart",
+	"forms",
+	"payment-web",
+	"rss",
+	"submit",
+	"api-app-mobile",
+	"account-creation-app-mobile",
+	"api-app-mobile-login",
+	"cart-app-mobile",
+	"forms-app-mobile",
+	"payment-app-mobile",
+	"agentic-general",
+	"agentic-account-creation",
+	"agentic-login",
+	"agentic-cart",
+	"agentic-forms",
+	"agentic-payment",
+	"api",
+}
+
 // resourceCustomRule define the CRUD operations and the schema definition for DataDome custom rules.
 func resourceCustomRule() *schema.Resource {
 	return &schema.Resource{
@@ -21,231 +57,608 @@ func resourceCustomRule() *schema.Resource {
 		ReadContext:   resourceCustomRuleRead,
 		UpdateContext: resourceCustomRuleUpdate,
 		DeleteContext: resourceCustomRuleDelete,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotWhiteSpace,
-			},
-			"query": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"response": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringInSlice([]string{"allow", "captcha", "block", "device_check", "intent_based", "monetize"}, false),
-			},
-			"priority": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringInSlice([]string{"high", "normal", "low"}, false),
-				Default:      "high",
-			},
-			"endpoint_type": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ValidateFunc: validation.StringInSlice([]string{
-					"web",
-					"account-creation",
-					"login",
-					"cart",
-					"forms",
-					"payment-web",
-					"rss",
-					"submit",
-					"api-app-mobile",
-					"account-creation-app-mobile",
-					"api-app-mobile-login",
-					"cart-app-mobile",
-					"forms-app-mobile",
-					"payment-app-mobile",
-					"agentic-general",
-					"agentic-account-creation",
-					"agentic-login",
-					"agentic-cart",
-					"agentic-forms",
-					"agentic-payment",
-					"api",
-				}, false),
-			},
-			"enabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				ForceNew: true,
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceCustomRuleV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceCustomRuleStateUpgradeV0,
 			},
-			"activated_at": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ValidateDiagFunc: func(v any, p cty.Path) diag.Diagnostics {
-					var diags diag.Diagnostics
-					value := v.(string)
-					if value != "" {
-						parsedTime, err := time.Parse(time.DateTime, value)
-						if err != nil {
-							diag := diag.Diagnostic{
-								Severity: diag.Error,
-								Summary:  "invalid date format",
-								Detail:   fmt.Sprintf("date '%s' does not match the required format 'YYYY-MM-DD HH:MM:SS'", value),
-							}
-							diags = append(diags, diag)
-						} else {
-							if parsedTime.Before(time.Now().UTC()) {
-								diag := diag.Diagnostic{
-									Severity: diag.Error,
-									Summary:  "invalid date",
-									Detail:   fmt.Sprintf("date '%s' must not be in the past", value),
-								}
-								diags = append(diags, diag)
-							}
-						}
-					}
-					return diags
-				},
-			},
-			"expired_at": {
-				Type:     schema.TypeString,
-				Optional: true,
-				ValidateDiagFunc: func(v any, p cty.Path) diag.Diagnostics {
-					var diags diag.Diagnostics
-					value := v.(string)
-					if value != "" {
-						parsedTime, err := time.Parse(time.DateTime, value)
-						if err != nil {
-							diag := diag.Diagnostic{
-								Severity: diag.Error,
-								Summary:  "invalid date format",
-								Detail:   fmt.Sprintf("date '%s' does not match the required format 'YYYY-MM-DD HH:MM:SS'", value),
-							}
-							diags = append(diags, diag)
-						} else {
-							if parsedTime.Before(time.Now().UTC()) {
-								diag := diag.Diagnostic{
-									Severity: diag.Error,
-									Summary:  "invalid date",
-									Detail:   fmt.Sprintf("date '%s' must not be in the past", value),
-								}
-								diags = append(diags, diag)
-							}
-						}
-					}
-					return diags
-				},
-			},
-			"overridden_bot": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"uuid": {
-							Type:         schema.TypeString,
-							Required:     true,
-							ValidateFunc: validation.IsUUID,
-						},
-						"name": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
+		},
+		Schema: resourceCustomRuleSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceCustomRuleImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Read:   schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffCustomRules,
+	}
+}
+
+// resourceCustomRuleSchema returns the schema definition of DataDome custom rules
+func resourceCustomRuleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ExactlyOneOf: []string{"name", "name_prefix"},
+			ValidateFunc: validation.StringIsNotWhiteSpace,
+		},
+		"name_prefix": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.StringIsNotWhiteSpace,
+		},
+		"query": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ExactlyOneOf: []string{"query", "match"},
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"match": {
+			Type:         schema.TypeList,
+			Optional:     true,
+			ExactlyOneOf: []string{"query", "match"},
+			Elem:         customRuleMatchSchema(customRuleMatchMaxDepth),
+		},
+		"response": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(customRuleResponses, false),
+		},
+		"priority": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringInSlice(customRulePriorities, false),
+			Default:      "high",
+		},
+		"endpoint_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Computed:     true,
+			ValidateFunc: validation.StringInSlice(customRuleEndpointTypes, false),
+		},
+		"endpoint_id": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"activated_at": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: validateCustomRuleDate,
+			StateFunc:        normalizeCustomRuleDateState,
+			DiffSuppressFunc: suppressEquivalentCustomRuleDates,
+			ConflictsWith:    []string{"activates_in"},
+		},
+		"expired_at": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Computed:         true,
+			ValidateDiagFunc: validateCustomRuleDate,
+			StateFunc:        normalizeCustomRuleDateState,
+			DiffSuppressFunc: suppressEquivalentCustomRuleDates,
+			ConflictsWith:    []string{"expires_in"},
+		},
+		"activates_in": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ValidateFunc:  validatePositiveDuration,
+			ConflictsWith: []string{"activated_at"},
+		},
+		"expires_in": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ValidateFunc:  validatePositiveDuration,
+			ConflictsWith: []string{"expired_at"},
+		},
+		"on_expiry": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "keep",
+			ValidateFunc: validation.StringInSlice([]string{"keep", "remove_from_state", "destroy"}, false),
+		},
+		"is_expired": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"overridden_bot": {
+			Type:     schema.TypeList,
+			Optional: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"uuid": {
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validation.IsUUID,
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
 					},
 				},
 			},
-			"policy_options": {
-				Type:     schema.TypeList,
-				Optional: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"time_box": {
-							Type:         schema.TypeList,
-							Optional:     true,
-							MaxItems:     1,
-							ExactlyOneOf: []string{"policy_options.0.time_box", "policy_options.0.rate_limit"},
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"authorized_hours_of_the_week": {
-										Type:     schema.TypeList,
-										Required: true,
-										MinItems: 1,
-										Elem: &schema.Schema{
-											Type:         schema.TypeInt,
-											ValidateFunc: validation.IntBetween(0, 167),
+		},
+		"policy_options": policyOptionsSchema("policy_options.0."),
+		"adopt_existing": {
+			Type:     schema.TypeBool,
+			Optional: true,
+		},
+	}
+}
+
+// policyOptionsSchema returns the schema of a policy_options block, located at the given path followed by "0.".
+// The constraints between its attributes are only declared with a path, as they cannot refer to blocks nested in lists.
+func policyOptionsSchema(path string) *schema.Schema {
+	exactlyOneOf := func(keys ...string) []string {
+		if path == "" {
+			return nil
+		}
+		result := make([]string, len(keys))
+		for i, key := range keys {
+			result[i] = path + key
+		}
+		return result
+	}
+
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"time_box": {
+					Type:         schema.TypeList,
+					Optional:     true,
+					MaxItems:     1,
+					ExactlyOneOf: exactlyOneOf("time_box", "rate_limit"),
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"authorized_hours_of_the_week": {
+								Type:         schema.TypeSet,
+								Optional:     true,
+								MinItems:     1,
+								Set:          schema.HashInt,
+								ExactlyOneOf: exactlyOneOf("time_box.0.authorized_hours_of_the_week", "time_box.0.schedule"),
+								Elem: &schema.Schema{
+									Type:         schema.TypeInt,
+									ValidateFunc: validation.IntBetween(0, 167),
+								},
+							},
+							"schedule": {
+								Type:         schema.TypeList,
+								Optional:     true,
+								MinItems:     1,
+								ExactlyOneOf: exactlyOneOf("time_box.0.authorized_hours_of_the_week", "time_box.0.schedule"),
+								Elem: &schema.Resource{
+									Schema: map[string]*schema.Schema{
+										"days": {
+											Type:     schema.TypeList,
+											Required: true,
+											MinItems: 1,
+											Elem: &schema.Schema{
+												Type:         schema.TypeString,
+												ValidateFunc: validation.StringInSlice(weekDays, true),
+											},
+										},
+										"start": {
+											Type:         schema.TypeString,
+											Required:     true,
+											ValidateFunc: validation.StringMatch(regexp.MustCompile(`^([01][0-9]|2[0-3]):00$`), "expected a time on the hour between 00:00 and 23:00"),
+										},
+										"end": {
+											Type:         schema.TypeString,
+											Required:     true,
+											ValidateFunc: validation.StringMatch(regexp.MustCompile(`^([01][0-9]|2[0-4]):00$`), "expected a time on the hour between 00:00 and 24:00"),
+										},
+										"timezone": {
+											Type:     schema.TypeString,
+											Optional: true,
+											Default:  "UTC",
+											ValidateFunc: func(v interface{}, k string) (warnings []string, errors []error) {
+												if _, err := time.LoadLocation(v.(string)); err != nil {
+													errors = append(errors, fmt.Errorf("expected %q to be a valid IANA time zone, got %q", k, v))
+												}
+												return warnings, errors
+											},
 										},
-									},
-									"response_outside_time_box": {
-										Type:         schema.TypeString,
-										Required:     true,
-										ValidateFunc: validation.StringInSlice([]string{"block", "captcha", "device_check"}, false),
 									},
 								},
 							},
+							"response_outside_time_box": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"block", "captcha", "device_check"}, false),
+							},
 						},
-						"rate_limit": {
-							Type:         schema.TypeList,
-							Optional:     true,
-							MaxItems:     1,
-							ExactlyOneOf: []string{"policy_options.0.time_box", "policy_options.0.rate_limit"},
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"applies_to": {
-										Type:         schema.TypeString,
-										Required:     true,
-										ValidateFunc: validation.StringInSlice([]string{"all_traffic", "ip", "session"}, false),
-									},
-									"threshold": {
-										Type:         schema.TypeInt,
-										Required:     true,
-										ValidateFunc: validation.IntAtLeast(1),
-									},
-									"time_frame": {
-										Type:         schema.TypeString,
-										Required:     true,
-										ValidateFunc: validation.StringInSlice([]string{"1m", "15m", "1h", "4h", "1d"}, false),
-									},
-									"response_after_threshold": {
-										Type:         schema.TypeString,
-										Required:     true,
-										ValidateFunc: validation.StringInSlice([]string{"block", "captcha", "device_check"}, false),
-									},
-								},
+					},
+				},
+				"rate_limit": {
+					Type:         schema.TypeList,
+					Optional:     true,
+					MaxItems:     1,
+					ExactlyOneOf: exactlyOneOf("time_box", "rate_limit"),
+					Elem: &schema.Resource{
+						Schema: map[string]*schema.Schema{
+							"applies_to": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"all_traffic", "ip", "session"}, false),
+							},
+							"threshold": {
+								Type:         schema.TypeInt,
+								Required:     true,
+								ValidateFunc: validation.IntAtLeast(1),
+							},
+							"time_frame": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"1m", "15m", "1h", "4h", "1d"}, false),
+							},
+							"response_after_threshold": {
+								Type:         schema.TypeString,
+								Required:     true,
+								ValidateFunc: validation.StringInSlice([]string{"block", "captcha", "device_check"}, false),
 							},
 						},
 					},
 				},
 			},
 		},
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
-		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(1 * time.Minute),
-			Read:   schema.DefaultTimeout(1 * time.Minute),
-			Update: schema.DefaultTimeout(1 * time.Minute),
-			Delete: schema.DefaultTimeout(1 * time.Minute),
-		},
-		CustomizeDiff: customizeDiffCustomRules,
 	}
 }
 
+// parseCustomRuleDate parses a date of the activated_at and expired_at fields.
+// It accepts the API format 'YYYY-MM-DD HH:MM:SS' in UTC, or an RFC 3339 date with a time zone.
+func parseCustomRuleDate(value string) (time.Time, error) {
+	if parsedTime, err := time.Parse(time.DateTime, value); err == nil {
+		return parsedTime, nil
+	}
+
+	parsedTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return parsedTime.UTC(), nil
+}
+
+// normalizeCustomRuleDate converts a date into the 'YYYY-MM-DD HH:MM:SS' UTC format expected by the API.
+// The value is returned unchanged when it cannot be parsed.
+func normalizeCustomRuleDate(value string) string {
+	parsedTime, err := parseCustomRuleDate(value)
+	if err != nil {
+		return value
+	}
+	return parsedTime.Format(time.DateTime)
+}
+
+// normalizeCustomRuleDateState is the StateFunc of the activated_at and expired_at fields
+func normalizeCustomRuleDateState(v any) string {
+	return normalizeCustomRuleDate(v.(string))
+}
+
+// validateCustomRuleDate checks that the activated_at and expired_at fields are valid dates
+func validateCustomRuleDate(v any, p cty.Path) diag.Diagnostics {
+	var diags diag.Diagnostics
+	value := v.(string)
+	if value == "" {
+		return diags
+	}
+
+	if _, err := parseCustomRuleDate(value); err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       "invalid date format",
+			Detail:        fmt.Sprintf("date '%s' does not match the required format 'YYYY-MM-DD HH:MM:SS' or RFC 3339", value),
+			AttributePath: p,
+		})
+	}
+
+	return diags
+}
+
+// suppressEquivalentCustomRuleDates suppresses the diff between two dates representing the same instant
+func suppressEquivalentCustomRuleDates(k, oldValue, newValue string, d *schema.ResourceData) bool {
+	oldTime, err := parseCustomRuleDate(oldValue)
+	if err != nil {
+		return false
+	}
+	newTime, err := parseCustomRuleDate(newValue)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
+
+// validatePositiveDuration checks that the activates_in and expires_in fields are positive Go durations
+func validatePositiveDuration(v interface{}, k string) (warnings []string, errors []error) {
+	value := v.(string)
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		errors = append(errors, fmt.Errorf("expected %q to be a valid duration such as \"48h\" or \"90m\", got %q", k, value))
+		return warnings, errors
+	}
+	if duration <= 0 {
+		errors = append(errors, fmt.Errorf("expected %q to be a positive duration, got %q", k, value))
+	}
+	return warnings, errors
+}
+
+// customRuleRelativeDateFields maps the activated_at and expired_at fields to the durations they can be resolved from
+var customRuleRelativeDateFields = map[string]string{
+	"activated_at": "activates_in",
+	"expired_at":   "expires_in",
+}
+
+// customizeDiffCustomRuleDates plans the activated_at and expired_at fields resolved from activates_in or expires_in
+// as unknown when the duration changes, as they are computed relative to the time of the apply.
+// The dates removed from the configuration, without a duration, are planned to be cleared.
+func customizeDiffCustomRuleDates(data *schema.ResourceDiff) error {
+	rawConfig := data.GetRawConfig()
+	if rawConfig.IsNull() {
+		return nil
+	}
+	for dateField, durationField := range customRuleRelativeDateFields {
+		if data.Get(durationField).(string) != "" {
+			if data.HasChange(durationField) {
+				if err := data.SetNewComputed(dateField); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if rawConfig.GetAttr(dateField).IsNull() && rawConfig.GetAttr(durationField).IsNull() && data.Get(dateField).(string) != "" {
+			if err := data.SetNew(dateField, ""); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveCustomRuleDate returns the value of dateField to send to the API.
+// When the matching duration is set, the date is computed relative to the current time on creation
+// and whenever the duration changes. Otherwise, the configured date, or the date stored in the state, is kept.
+func resolveCustomRuleDate(data *schema.ResourceData, dateField string) (*string, error) {
+	durationField := customRuleRelativeDateFields[dateField]
+	duration := data.Get(durationField).(string)
+	if duration == "" {
+		// a date removed from the configuration is planned as unknown, as it is computed
+		if rawConfig := data.GetRawConfig(); !rawConfig.IsNull() && rawConfig.GetAttr(dateField).IsNull() {
+			return nil, nil
+		}
+		return getOptionalCustomRuleDate(data, dateField), nil
+	}
+	if !data.HasChange(durationField) {
+		return getOptionalCustomRuleDate(data, dateField), nil
+	}
+
+	parsedDuration, err := time.ParseDuration(duration)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", durationField, err)
+	}
+
+	date := time.Now().UTC().Add(parsedDuration).Format(time.DateTime)
+	return &date, nil
+}
+
+// isCustomRuleExpired reports whether the given expiration date has passed
+func isCustomRuleExpired(expiredAt *string) bool {
+	if expiredAt == nil || *expiredAt == "" {
+		return false
+	}
+	expiredTime, err := parseCustomRuleDate(*expiredAt)
+	if err != nil {
+		return false
+	}
+	return !expiredTime.After(time.Now().UTC())
+}
+
+// isCustomRuleInactive reports whether the given activation date is still ahead
+func isCustomRuleInactive(activatedAt *string) bool {
+	if activatedAt == nil || *activatedAt == "" {
+		return false
+	}
+	activatedTime, err := parseCustomRuleDate(*activatedAt)
+	if err != nil {
+		return false
+	}
+	return activatedTime.After(time.Now().UTC())
+}
+
+// getOptionalCustomRuleDate returns the normalized value of a date field, or nil when the field is not set
+func getOptionalCustomRuleDate(data *schema.ResourceData, field string) *string {
+	value := common.GetOptionalValue[string](data, field)
+	if value == nil {
+		return nil
+	}
+	normalized := normalizeCustomRuleDate(*value)
+	return &normalized
+}
+
+// checkVerifiedBotExists raises a plan warning when no verified bot has the given UUID
+func checkVerifiedBotExists(ctx context.Context, c common.ListAPI[dd.VerifiedBot], uuid string) {
+	if c == nil {
+		return
+	}
+	verifiedBot, err := findVerifiedBot(ctx, c, uuid)
+	if err != nil {
+		log.Printf("[WARN] unable to list verified bots to check overridden_bot %s: %s", uuid, err)
+		return
+	}
+	if verifiedBot == nil {
+		addPlanWarning(ctx,
+			"Unknown overridden bot",
+			fmt.Sprintf("No verified bot or AI agent has the UUID %q set in overridden_bot. Use the datadome_verified_bots data source to look up the UUID by name or category.", uuid),
+		)
+	}
+}
+
+// customizeDiffCustomRuleExpiry handles the custom rules whose expired_at date has passed, according to on_expiry:
+// - "keep" raises a warning, the expired rule is left in the account and in the state
+// - "destroy" plans the replacement of the expired rule, which deletes it and creates it again for a new expires_in period
+// The "remove_from_state" rules are removed from the state by the read, and never reach the plan once expired.
+func customizeDiffCustomRuleExpiry(ctx context.Context, data *schema.ResourceDiff) error {
+	if data.Id() == "" || !data.Get("is_expired").(bool) {
+		return nil
+	}
+
+	name := data.Get("name").(string)
+	if data.Get("on_expiry").(string) != "destroy" {
+		addPlanWarning(ctx,
+			"Expired custom rule",
+			fmt.Sprintf("The custom rule %q has expired, it no longer applies to any request. Remove it from the configuration to delete it from the account, or set on_expiry to clean it up.", name),
+		)
+		return nil
+	}
+
+	// the replacement creates the custom rule with the configured dates, which are only renewed by expires_in
+	if data.Get("expires_in").(string) == "" {
+		return fmt.Errorf(`on_expiry: the custom rule %q has expired and is replaced with the same expired_at date, set expires_in or remove the custom rule from the configuration`, name)
+	}
+
+	log.Printf("[INFO] custom rule %q expired, planning its replacement", name)
+	if err := data.SetNew("is_expired", false); err != nil {
+		return err
+	}
+	return data.ForceNew("is_expired")
+}
+
 // customizeDiffCustomRules applies additional verifications regarding the fields of the custom rule.
+// It plans the replacement of the expired custom rule when on_expiry is "destroy", see customizeDiffCustomRuleExpiry,
+// and compiles the match blocks into the query.
+// It raises a warning when the custom rule has expired, when overridden_bot is not a known verified bot,
+// and when the custom rule conflicts with or is shadowed by another custom rule, which is an error in strict mode.
+// It raises an error when the fields are inconsistent, see validateCustomRuleDiff, and when:
+// - a match block is neither a condition nor a single any or all group
+// - endpoint_type contradicts the source and traffic usage of the endpoint referenced by endpoint_id
+// - the name is already used by another custom rule of the account
+// The fields are checked before the checks calling the API, which share a single list of the custom rules.
+func customizeDiffCustomRules(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
+	if err := customizeDiffCustomRuleExpiry(ctx, data); err != nil {
+		return err
+	}
+
+	// match blocks are compiled into the query, which is unknown until all their values are known
+	if matches, ok := data.GetOk("match"); ok {
+		if rawConfig := data.GetRawConfig(); !rawConfig.IsNull() && !rawConfig.GetAttr("match").IsWhollyKnown() {
+			if err := data.SetNewComputed("query"); err != nil {
+				return err
+			}
+		} else {
+			compiled, err := compileCustomRuleMatches(matches.([]interface{}))
+			if err != nil {
+				return err
+			}
+			if query.Canonical(data.Get("query").(string)) != compiled {
+				if err = data.SetNew("query", compiled); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := customizeDiffCustomRuleDates(data); err != nil {
+		return err
+	}
+
+	if err := validateCustomRuleDiff(data); err != nil {
+		return err
+	}
+
+	if err := customizeDiffCustomRuleEndpointType(ctx, data, meta); err != nil {
+		return err
+	}
+
+	// overridden_bot is only looked up when it changes, and only raises a warning as the list of verified bots evolves
+	if data.HasChange("overridden_bot.0.uuid") {
+		if uuid := data.Get("overridden_bot.0.uuid").(string); uuid != "" {
+			checkVerifiedBotExists(ctx, meta.(*ProviderConfig).ClientVerifiedBot, uuid)
+		}
+	}
+
+	list := listOnce(ctx, meta.(*ProviderConfig).ClientCustomRule)
+	replacesExpired := data.Get("on_expiry").(string) == "destroy"
+	err := checkNameAvailable(data, list, "custom rule", func(customRule dd.CustomRule) (string, string) {
+		// the replacement of an expired custom rule is planned as a creation, while the expired rule still holds the name
+		if replacesExpired && isCustomRuleExpired(customRule.ExpiredAt) {
+			return "", ""
+		}
+		if customRule.ID == nil {
+			return customRule.Name, ""
+		}
+		return customRule.Name, strconv.Itoa(*customRule.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	return checkCustomRuleConflicts(ctx, data, meta, list)
+}
+
+// validateCustomRuleDiff checks the consistency of the planned fields of the custom rule, without calling the API.
 // It raises an error when:
+// - activated_at or expired_at is changed to a date in the past
 // - expired_at is before activated_at
+// - expires_in is not greater than activates_in
+// - overridden_bot is not set and response is "monetize" or "intent_based"
+// - policy_options is set and response is not "allow" or "intent_based"
+// - a time_box schedule ends at the time it starts
 // - overridden_bot is set and rate_limit.applies_to is not "all_traffic"
 // - rate_limit.applies_to is "all_traffic" and time_frame is not "1h" or "1d"
 // - rate_limit.applies_to is "ip" or "session" and time_frame is not "1m", "15m", or "4h"
-func customizeDiffCustomRules(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
+func validateCustomRuleDiff(data *schema.ResourceDiff) error {
+	// Dates are only checked against the current time when they change,
+	// so that rules whose activation date has passed can still be planned.
+	for _, field := range []string{"activated_at", "expired_at"} {
+		oldValue, newValue := data.GetChange(field)
+		value := newValue.(string)
+		if value == "" || suppressEquivalentCustomRuleDates(field, oldValue.(string), value, nil) {
+			continue
+		}
+		parsedTime, err := parseCustomRuleDate(value)
+		if err == nil && parsedTime.Before(time.Now().UTC()) {
+			return fmt.Errorf("%s: date '%s' must not be in the past", field, value)
+		}
+	}
+
 	activatedAt := data.Get("activated_at").(string)
 	expiredAt := data.Get("expired_at").(string)
 
 	if activatedAt != "" && expiredAt != "" {
-		activatedTime, _ := time.Parse(time.DateTime, activatedAt)
-		expiredTime, _ := time.Parse(time.DateTime, expiredAt)
+		activatedTime, _ := parseCustomRuleDate(activatedAt)
+		expiredTime, _ := parseCustomRuleDate(expiredAt)
 		if activatedTime.After(expiredTime) {
 			return fmt.Errorf("expired_at date must be after activated_at date")
 		}
 	}
 
+	activatesIn := data.Get("activates_in").(string)
+	expiresIn := data.Get("expires_in").(string)
+
+	if activatesIn != "" && expiresIn != "" {
+		activatesInDuration, _ := time.ParseDuration(activatesIn)
+		expiresInDuration, _ := time.ParseDuration(expiresIn)
+		if activatesInDuration >= expiresInDuration {
+			return fmt.Errorf("expires_in duration must be greater than activates_in duration")
+		}
+	}
+
 	response := data.Get("response").(string)
 
 	// overridden_bot required for monetize and intent_based responses
@@ -263,6 +676,16 @@ func customizeDiffCustomRules(ctx context.Context, data *schema.ResourceDiff, me
 		}
 	}
 
+	if schedules, ok := data.GetOk("policy_options.0.time_box.0.schedule"); ok {
+		for i, v := range schedules.([]interface{}) {
+			schedule := v.(map[string]interface{})
+			start := schedule["start"].(string)
+			if start != "" && start == schedule["end"].(string) {
+				return fmt.Errorf("policy_options.0.time_box.0.schedule.%d: the end time must differ from the start time, got %q", i, start)
+			}
+		}
+	}
+
 	rlRaw, hasRateLimit := data.GetOk("policy_options.0.rate_limit.0.applies_to")
 	if !hasRateLimit {
 		return nil
@@ -278,6 +701,11 @@ func customizeDiffCustomRules(ctx context.Context, data *schema.ResourceDiff, me
 		}
 	}
 
+	return checkRateLimitTimeFrame(appliesTo, timeFrame)
+}
+
+// checkRateLimitTimeFrame checks that the time frame of a rate limit is supported by the scope it applies to
+func checkRateLimitTimeFrame(appliesTo, timeFrame string) error {
 	switch appliesTo {
 	case "all_traffic":
 		if timeFrame != "1h" && timeFrame != "1d" {
@@ -288,20 +716,35 @@ func customizeDiffCustomRules(ctx context.Context, data *schema.ResourceDiff, me
 			return fmt.Errorf("rate_limit.time_frame must be \"1m\", \"15m\", or \"4h\" when applies_to is %q, got %q", appliesTo, timeFrame)
 		}
 	}
-
 	return nil
 }
 
+// expandTimeBoxSchedules converts the schedule blocks of a time_box into weekly schedules
+func expandTimeBoxSchedules(raw []interface{}) []weeklySchedule {
+	schedules := make([]weeklySchedule, 0, len(raw))
+	for _, v := range raw {
+		block := v.(map[string]interface{})
+		daysRaw := block["days"].([]interface{})
+		days := make([]string, len(daysRaw))
+		for i, d := range daysRaw {
+			days[i] = d.(string)
+		}
+		schedules = append(schedules, weeklySchedule{
+			Days:     days,
+			Start:    block["start"].(string),
+			End:      block["end"].(string),
+			Timezone: block["timezone"].(string),
+		})
+	}
+	return schedules
+}
+
 // expandPolicyOptions converts the Terraform schema data for policy_options into a *dd.PolicyOptions.
+// A time_box schedule is expanded into the authorized hours of the week in UTC.
 // Returns nil when the block is absent or empty.
-func expandPolicyOptions(data *schema.ResourceData) *dd.PolicyOptions {
-	raw, ok := data.GetOk("policy_options")
-	if !ok {
-		return nil
-	}
-	list := raw.([]interface{})
-	if len(list) == 0 {
-		return nil
+func expandPolicyOptions(list []interface{}) (*dd.PolicyOptions, error) {
+	if len(list) == 0 || list[0] == nil {
+		return nil, nil
 	}
 	block := list[0].(map[string]interface{})
 
@@ -309,11 +752,19 @@ func expandPolicyOptions(data *schema.ResourceData) *dd.PolicyOptions {
 
 	if v, ok := block["time_box"].([]interface{}); ok && len(v) > 0 {
 		tb := v[0].(map[string]interface{})
-		hoursRaw := tb["authorized_hours_of_the_week"].([]interface{})
+		hoursRaw := tb["authorized_hours_of_the_week"].(*schema.Set).List()
 		hours := make([]int, len(hoursRaw))
 		for i, h := range hoursRaw {
 			hours[i] = h.(int)
 		}
+		hours = normalizeHoursOfWeek(hours)
+		if schedules := tb["schedule"].([]interface{}); len(schedules) > 0 {
+			var err error
+			hours, err = expandWeeklySchedules(expandTimeBoxSchedules(schedules), time.Now())
+			if err != nil {
+				return nil, fmt.Errorf("policy_options.0.time_box.0.schedule: %w", err)
+			}
+		}
 		po.TimeBox = &dd.TimeBoxOptions{
 			AuthorizedHoursOfTheWeek: hours,
 			ResponseOutsideTimeBox:   tb["response_outside_time_box"].(string),
@@ -331,14 +782,15 @@ func expandPolicyOptions(data *schema.ResourceData) *dd.PolicyOptions {
 	}
 
 	if po.TimeBox == nil && po.RateLimit == nil {
-		return nil
+		return nil, nil
 	}
-	return po
+	return po, nil
 }
 
 // flattenPolicyOptions converts a *dd.PolicyOptions into the list-of-maps representation
-// expected by the Terraform schema. Returns nil when po is nil.
-func flattenPolicyOptions(po *dd.PolicyOptions) []interface{} {
+// expected by the Terraform schema. The given time_box schedules are kept instead of the
+// authorized hours of the week when they expand into the same hours. Returns nil when po is nil.
+func flattenPolicyOptions(po *dd.PolicyOptions, schedules []interface{}) []interface{} {
 	if po == nil {
 		return nil
 	}
@@ -349,16 +801,23 @@ func flattenPolicyOptions(po *dd.PolicyOptions) []interface{} {
 	}
 
 	if po.TimeBox != nil {
-		hours := make([]interface{}, len(po.TimeBox.AuthorizedHoursOfTheWeek))
-		for i, h := range po.TimeBox.AuthorizedHoursOfTheWeek {
-			hours[i] = h
+		hours := make([]interface{}, 0, len(po.TimeBox.AuthorizedHoursOfTheWeek))
+		for _, h := range normalizeHoursOfWeek(po.TimeBox.AuthorizedHoursOfTheWeek) {
+			hours = append(hours, h)
 		}
-		block["time_box"] = []interface{}{
-			map[string]interface{}{
-				"authorized_hours_of_the_week": hours,
-				"response_outside_time_box":    po.TimeBox.ResponseOutsideTimeBox,
-			},
+		timeBox := map[string]interface{}{
+			"authorized_hours_of_the_week": hours,
+			"schedule":                     []interface{}{},
+			"response_outside_time_box":    po.TimeBox.ResponseOutsideTimeBox,
 		}
+		if len(schedules) > 0 {
+			scheduleHours, err := expandWeeklySchedules(expandTimeBoxSchedules(schedules), time.Now())
+			if err == nil && slices.Equal(scheduleHours, normalizeHoursOfWeek(po.TimeBox.AuthorizedHoursOfTheWeek)) {
+				timeBox["authorized_hours_of_the_week"] = []interface{}{}
+				timeBox["schedule"] = schedules
+			}
+		}
+		block["time_box"] = []interface{}{timeBox}
 	}
 
 	if po.RateLimit != nil {
@@ -380,20 +839,34 @@ func resourceCustomRuleCreate(ctx context.Context, data *schema.ResourceData, me
 	config := meta.(*ProviderConfig)
 	c := config.ClientCustomRule
 
-	activatedAt := common.GetOptionalValue[string](data, "activated_at")
+	activatedAt, err := resolveCustomRuleDate(data, "activated_at")
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	enabled := common.GetOptionalValue[bool](data, "enabled")
-	expiredAt := common.GetOptionalValue[string](data, "expired_at")
+	expiredAt, err := resolveCustomRuleDate(data, "expired_at")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	policyOptions, err := expandPolicyOptions(data.Get("policy_options").([]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	endpointType, err := resolveCustomRuleEndpointType(ctx, data, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	newCustomRule := dd.CustomRule{
-		Name:          data.Get("name").(string),
+		Name:          resolveResourceName(data),
 		Response:      data.Get("response").(string),
 		Query:         data.Get("query").(string),
-		EndpointType:  data.Get("endpoint_type").(string),
+		EndpointType:  endpointType,
 		Priority:      data.Get("priority").(string),
 		Enabled:       enabled,
 		ActivatedAt:   activatedAt,
 		ExpiredAt:     expiredAt,
-		PolicyOptions: expandPolicyOptions(data),
+		PolicyOptions: policyOptions,
 	}
 
 	if v, ok := data.GetOk("overridden_bot"); ok {
@@ -404,6 +877,22 @@ func resourceCustomRuleCreate(ctx context.Context, data *schema.ResourceData, me
 		}
 	}
 
+	if data.Get("adopt_existing").(bool) {
+		existing, err := findCustomRuleByName(ctx, c, newCustomRule.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if existing != nil {
+			log.Printf("[INFO] adopting existing custom rule %q with ID %d", existing.Name, *existing.ID)
+			newCustomRule.ID = existing.ID
+			if _, err = c.Update(ctx, newCustomRule); err != nil {
+				return diag.FromErr(err)
+			}
+			data.SetId(strconv.Itoa(*existing.ID))
+			return resourceCustomRuleRead(ctx, data, meta)
+		}
+	}
+
 	id, err := c.Create(ctx, newCustomRule)
 	if err != nil {
 		return diag.FromErr(err)
@@ -414,6 +903,59 @@ func resourceCustomRuleCreate(ctx context.Context, data *schema.ResourceData, me
 	return resourceCustomRuleRead(ctx, data, meta)
 }
 
+// findCustomRuleByName returns the custom rule with the given name, or nil when none exists.
+// It raises an error when several custom rules share the same name.
+func findCustomRuleByName(ctx context.Context, c common.API[dd.CustomRule, int], name string) (*dd.CustomRule, error) {
+	customRules, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*dd.CustomRule
+	for i := range customRules {
+		if customRules[i].Name == name {
+			matches = append(matches, &customRules[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = strconv.Itoa(*m.ID)
+		}
+		return nil, fmt.Errorf("multiple custom rules are named %q (IDs: %s)", name, strings.Join(ids, ", "))
+	}
+}
+
+// resourceCustomRuleImport is used to import a custom rule by its numeric ID, or by its name with the "name:<rule name>" syntax
+func resourceCustomRuleImport(ctx context.Context, data *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	importID := data.Id()
+
+	if name, ok := strings.CutPrefix(importID, importByNamePrefix); ok {
+		config := meta.(*ProviderConfig)
+		customRule, err := findCustomRuleByName(ctx, config.ClientCustomRule, name)
+		if err != nil {
+			return nil, err
+		}
+		if customRule == nil {
+			return nil, fmt.Errorf("no custom rule found with name %q", name)
+		}
+		data.SetId(strconv.Itoa(*customRule.ID))
+		return []*schema.ResourceData{data}, nil
+	}
+
+	if _, err := strconv.Atoi(importID); err != nil {
+		return nil, fmt.Errorf("unexpected import ID %q, expected a numeric custom rule ID or %q", importID, importByNamePrefix+"<rule name>")
+	}
+
+	return []*schema.ResourceData{data}, nil
+}
+
 // resourceCustomRuleRead is used to fetch the custom rule by its ID
 func resourceCustomRuleRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	config := meta.(*ProviderConfig)
@@ -431,6 +973,12 @@ func resourceCustomRuleRead(ctx context.Context, data *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
+	if customRule == nil || customRule.ID == nil {
+		log.Printf("[WARN] custom rule %d not found, removing it from the state", id)
+		data.SetId("")
+		return diags
+	}
+
 	if err = data.Set("name", customRule.Name); err != nil {
 		return diag.FromErr(err)
 	}
@@ -440,6 +988,25 @@ func resourceCustomRuleRead(ctx context.Context, data *schema.ResourceData, meta
 	if err = data.Set("query", customRule.Query); err != nil {
 		return diag.FromErr(err)
 	}
+	// match blocks are kept while they compile into the query of the API, and are rebuilt from the query otherwise
+	if matches := data.Get("match").([]interface{}); len(matches) > 0 {
+		compiled, err := compileCustomRuleMatches(matches)
+		if err != nil || compiled != query.Canonical(customRule.Query) {
+			flattened, ok := flattenCustomRuleMatches(customRule.Query)
+			if !ok {
+				// the match blocks are kept, so the plan restores the query they compile into
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Query not representable by match blocks",
+					Detail: fmt.Sprintf("The query of the custom rule %q changed outside of Terraform to %q, which match blocks cannot express. "+
+						"The match blocks are kept and the next apply restores the query they compile into.", customRule.Name, customRule.Query),
+					AttributePath: cty.GetAttrPath("match"),
+				})
+			} else if err = data.Set("match", flattened); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
 	if err = data.Set("endpoint_type", customRule.EndpointType); err != nil {
 		return diag.FromErr(err)
 	}
@@ -469,10 +1036,27 @@ func resourceCustomRuleRead(ctx context.Context, data *schema.ResourceData, meta
 		return diag.FromErr(err)
 	}
 
-	if err = data.Set("policy_options", flattenPolicyOptions(customRule.PolicyOptions)); err != nil {
+	schedules := data.Get("policy_options.0.time_box.0.schedule").([]interface{})
+	if err = data.Set("policy_options", flattenPolicyOptions(customRule.PolicyOptions, schedules)); err != nil {
 		return diag.FromErr(err)
 	}
 
+	// on_expiry is not returned by the API, the default value is set on import
+	if data.Get("on_expiry").(string) == "" {
+		if err = data.Set("on_expiry", "keep"); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	isExpired := isCustomRuleExpired(customRule.ExpiredAt)
+	if err = data.Set("is_expired", isExpired); err != nil {
+		return diag.FromErr(err)
+	}
+	if isExpired && data.Get("on_expiry").(string) == "remove_from_state" {
+		log.Printf("[INFO] custom rule %d expired, removing it from the state", id)
+		data.SetId("")
+	}
+
 	return diags
 }
 
@@ -486,21 +1070,35 @@ func resourceCustomRuleUpdate(ctx context.Context, data *schema.ResourceData, me
 		return diag.FromErr(err)
 	}
 
-	activatedAt := common.GetOptionalValue[string](data, "activated_at")
+	activatedAt, err := resolveCustomRuleDate(data, "activated_at")
+	if err != nil {
+		return diag.FromErr(err)
+	}
 	enabled := common.GetOptionalValue[bool](data, "enabled")
-	expiredAt := common.GetOptionalValue[string](data, "expired_at")
+	expiredAt, err := resolveCustomRuleDate(data, "expired_at")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	policyOptions, err := expandPolicyOptions(data.Get("policy_options").([]interface{}))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	endpointType, err := resolveCustomRuleEndpointType(ctx, data, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	newCustomRule := dd.CustomRule{
 		ID:            &id,
 		Name:          data.Get("name").(string),
 		Response:      data.Get("response").(string),
 		Query:         data.Get("query").(string),
-		EndpointType:  data.Get("endpoint_type").(string),
+		EndpointType:  endpointType,
 		Priority:      data.Get("priority").(string),
 		Enabled:       enabled,
 		ActivatedAt:   activatedAt,
 		ExpiredAt:     expiredAt,
-		PolicyOptions: expandPolicyOptions(data),
+		PolicyOptions: policyOptions,
 	}
 
 	if v, ok := data.GetOk("overridden_bot"); ok {
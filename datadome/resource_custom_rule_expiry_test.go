@@ -0,0 +1,91 @@
+package datadome
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// testReadExpiredCustomRule reads an expired custom rule with the given configuration
+func testReadExpiredCustomRule(t *testing.T, customRule map[string]interface{}) (*schema.ResourceData, *ProviderConfig) {
+	mockClient := dd.NewMockClientCustomRule()
+	expiredAt := "2020-01-01 00:00:00"
+	id, err := mockClient.Create(context.Background(), dd.CustomRule{
+		Name:         customRule["name"].(string),
+		Query:        customRule["query"].(string),
+		Response:     customRule["response"].(string),
+		EndpointType: customRule["endpoint_type"].(string),
+		Priority:     customRule["priority"].(string),
+		ExpiredAt:    &expiredAt,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ProviderConfig{ClientCustomRule: mockClient, ClientEndpoint: dd.NewMockClientEndpoint()}
+
+	data := schema.TestResourceDataRaw(t, resourceCustomRule().Schema, customRule)
+	data.SetId(strconv.Itoa(*id))
+	diags := resourceCustomRuleRead(context.Background(), data, config)
+	assert.Empty(t, diags)
+	return data, config
+}
+
+// testDiffCustomRule plans the changes of the custom rule stored in data against the given configuration
+func testDiffCustomRule(data *schema.ResourceData, config *ProviderConfig, customRule map[string]interface{}) (*terraform.InstanceDiff, error) {
+	return resourceCustomRule().Diff(context.Background(), data.State(), terraform.NewResourceConfigRaw(customRule), config)
+}
+
+func testExpiringCustomRuleConfig(onExpiry string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":          "acc-test",
+		"query":         "ip: 192.168.0.1",
+		"response":      "block",
+		"endpoint_type": "web",
+		"priority":      "low",
+		"expires_in":    "1h",
+		"on_expiry":     onExpiry,
+	}
+}
+
+func TestCustomRuleOnExpiry_keep(t *testing.T) {
+	customRule := testExpiringCustomRuleConfig("keep")
+
+	data, config := testReadExpiredCustomRule(t, customRule)
+
+	assert.NotEmpty(t, data.Id())
+	assert.True(t, data.Get("is_expired").(bool))
+
+	diff, err := testDiffCustomRule(data, config, customRule)
+	assert.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+}
+
+func TestCustomRuleOnExpiry_removeFromState(t *testing.T) {
+	data, _ := testReadExpiredCustomRule(t, testExpiringCustomRuleConfig("remove_from_state"))
+
+	assert.Empty(t, data.Id())
+}
+
+func TestCustomRuleOnExpiry_destroy(t *testing.T) {
+	customRule := testExpiringCustomRuleConfig("destroy")
+
+	data, config := testReadExpiredCustomRule(t, customRule)
+
+	assert.NotEmpty(t, data.Id())
+	diff, err := testDiffCustomRule(data, config, customRule)
+	if assert.NoError(t, err) {
+		assert.True(t, diff.RequiresNew())
+		assert.True(t, diff.Attributes["is_expired"].RequiresNew)
+	}
+
+	// the replacement would create the custom rule with the same expired_at date
+	delete(customRule, "expires_in")
+	customRule["expired_at"] = "2020-01-01 00:00:00"
+	_, err = testDiffCustomRule(data, config, customRule)
+	assert.ErrorContains(t, err, `on_expiry: the custom rule "acc-test" has expired`)
+}
@@ -0,0 +1,170 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceCustomRuleV0 returns the schema of custom rules before version 1,
+// where authorized_hours_of_the_week was an ordered list.
+// It is a frozen copy of the version 0 schema, which must not follow the changes of the current schema.
+func resourceCustomRuleV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"response": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"priority": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "high",
+			},
+			"endpoint_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				ForceNew: true,
+			},
+			"activated_at": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"expired_at": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"overridden_bot": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"policy_options": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"time_box": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"authorized_hours_of_the_week": {
+										Type:     schema.TypeList,
+										Required: true,
+										MinItems: 1,
+										Elem:     &schema.Schema{Type: schema.TypeInt},
+									},
+									"response_outside_time_box": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+								},
+							},
+						},
+						"rate_limit": {
+							Type:     schema.TypeList,
+							Optional: true,
+							MaxItems: 1,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"applies_to": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"threshold": {
+										Type:     schema.TypeInt,
+										Required: true,
+									},
+									"time_frame": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"response_after_threshold": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// resourceCustomRuleStateUpgradeV0 sorts and removes the duplicates of authorized_hours_of_the_week,
+// so the list stored in a version 0 state matches the set of version 1 without any plan change
+func resourceCustomRuleStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	policyOptions, _ := rawState["policy_options"].([]interface{})
+	for _, po := range policyOptions {
+		po, ok := po.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		timeBoxes, _ := po["time_box"].([]interface{})
+		for _, tb := range timeBoxes {
+			tb, ok := tb.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			hoursRaw, ok := tb["authorized_hours_of_the_week"].([]interface{})
+			if !ok {
+				continue
+			}
+			hours := make([]int, 0, len(hoursRaw))
+			for _, h := range hoursRaw {
+				hour, ok := h.(float64)
+				if !ok {
+					return nil, fmt.Errorf("unexpected authorized hour %v of type %T in the state", h, h)
+				}
+				hours = append(hours, int(hour))
+			}
+			normalized := normalizeHoursOfWeek(hours)
+			if !slices.Equal(hours, normalized) {
+				log.Printf("[DEBUG] normalizing authorized hours of the week of custom rule %v from %v to %v", rawState["id"], hours, normalized)
+			}
+			upgraded := make([]interface{}, 0, len(normalized))
+			for _, hour := range normalized {
+				upgraded = append(upgraded, hour)
+			}
+			tb["authorized_hours_of_the_week"] = upgraded
+		}
+	}
+
+	return rawState, nil
+}
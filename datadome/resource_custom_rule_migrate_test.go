@@ -0,0 +1,58 @@
+package datadome
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceCustomRuleStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":   "1",
+		"name": "acc-test",
+		"policy_options": []interface{}{
+			map[string]interface{}{
+				"time_box": []interface{}{
+					map[string]interface{}{
+						"authorized_hours_of_the_week": []interface{}{float64(17), float64(9), float64(9), float64(10)},
+						"response_outside_time_box":    "block",
+					},
+				},
+				"rate_limit": []interface{}{},
+			},
+		},
+	}
+
+	upgraded, err := resourceCustomRuleStateUpgradeV0(context.Background(), rawState, nil)
+	assert.NoError(t, err)
+
+	timeBox := upgraded["policy_options"].([]interface{})[0].(map[string]interface{})["time_box"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{9, 10, 17}, timeBox["authorized_hours_of_the_week"])
+	assert.Equal(t, "block", timeBox["response_outside_time_box"])
+	assert.Equal(t, "acc-test", upgraded["name"])
+}
+
+func TestResourceCustomRuleStateUpgradeV0_WithoutPolicyOptions(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":             "1",
+		"name":           "acc-test",
+		"policy_options": []interface{}{},
+	}
+
+	upgraded, err := resourceCustomRuleStateUpgradeV0(context.Background(), rawState, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, rawState, upgraded)
+}
+
+func TestResourceCustomRuleV0_AuthorizedHoursIsList(t *testing.T) {
+	ty := resourceCustomRuleV0().CoreConfigSchema().ImpliedType()
+	timeBox := ty.AttributeType("policy_options").ElementType().AttributeType("time_box").ElementType()
+	assert.True(t, timeBox.AttributeType("authorized_hours_of_the_week").IsListType())
+	assert.False(t, timeBox.HasAttribute("schedule"))
+	assert.False(t, ty.HasAttribute("match"))
+
+	ty = resourceCustomRule().CoreConfigSchema().ImpliedType()
+	timeBox = ty.AttributeType("policy_options").ElementType().AttributeType("time_box").ElementType()
+	assert.True(t, timeBox.AttributeType("authorized_hours_of_the_week").IsSetType())
+}
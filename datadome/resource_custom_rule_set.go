@@ -0,0 +1,488 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/datadome/terraform-provider/common"
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// customRuleSetResponses lists the responses of the rules of a custom rule set, which cannot override a bot
+var customRuleSetResponses = []string{"allow", "captcha", "block", "device_check"}
+
+// resourceCustomRuleSet define the CRUD operations and the schema definition for collections of DataDome custom rules,
+// reconciled with a single list request instead of one request per rule.
+func resourceCustomRuleSet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceCustomRuleSetCreate,
+		ReadContext:   resourceCustomRuleSetRead,
+		UpdateContext: resourceCustomRuleSetUpdate,
+		DeleteContext: resourceCustomRuleSetDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"rule": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotWhiteSpace,
+						},
+						"query": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringIsNotEmpty,
+						},
+						"response": {
+							Type:         schema.TypeString,
+							Required:     true,
+							ValidateFunc: validation.StringInSlice(customRuleSetResponses, false),
+						},
+						"priority": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice(customRulePriorities, false),
+							Default:      "high",
+						},
+						"endpoint_type": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.StringInSlice(customRuleEndpointTypes, false),
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"policy_options": policyOptionsSchema(""),
+					},
+				},
+			},
+			"rule_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffCustomRuleSet,
+	}
+}
+
+// customizeDiffCustomRuleSet checks the rules of the set, whose policy options cannot declare constraints in the schema,
+// and marks the IDs of the rules as unknown when rules are added or removed
+func customizeDiffCustomRuleSet(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
+	names := map[string]bool{}
+	for _, v := range data.Get("rule").(*schema.Set).List() {
+		rule, _ := v.(map[string]interface{})
+		if rule == nil {
+			continue
+		}
+		name := rule["name"].(string)
+		if names[name] && name != "" {
+			return fmt.Errorf("rule: the name %q is used by several rules", name)
+		}
+		names[name] = true
+
+		if err := checkCustomRuleSetPolicyOptions(rule["policy_options"].([]interface{}), rule["response"].(string)); err != nil {
+			return fmt.Errorf("rule %q: %w", name, err)
+		}
+	}
+
+	oldRules, newRules := data.GetChange("rule")
+	if !slices.Equal(customRuleSetNames(oldRules.(*schema.Set).List()), customRuleSetNames(newRules.(*schema.Set).List())) {
+		return data.SetNewComputed("rule_ids")
+	}
+	return nil
+}
+
+// checkCustomRuleSetPolicyOptions checks the policy options of a rule of a set, as checked by the schema and the diff of a custom rule
+func checkCustomRuleSetPolicyOptions(policyOptions []interface{}, response string) error {
+	if len(policyOptions) == 0 || policyOptions[0] == nil {
+		return nil
+	}
+	if response != "allow" {
+		return fmt.Errorf("policy_options is only allowed when response is \"allow\"")
+	}
+
+	block := policyOptions[0].(map[string]interface{})
+	timeBoxes := block["time_box"].([]interface{})
+	rateLimits := block["rate_limit"].([]interface{})
+	if (len(timeBoxes) == 0) == (len(rateLimits) == 0) {
+		return fmt.Errorf("policy_options: exactly one of time_box or rate_limit must be specified")
+	}
+
+	if len(timeBoxes) > 0 && timeBoxes[0] != nil {
+		timeBox := timeBoxes[0].(map[string]interface{})
+		hours := timeBox["authorized_hours_of_the_week"].(*schema.Set)
+		schedules := timeBox["schedule"].([]interface{})
+		if (hours.Len() == 0) == (len(schedules) == 0) {
+			return fmt.Errorf("policy_options.0.time_box: exactly one of authorized_hours_of_the_week or schedule must be specified")
+		}
+		for i, v := range schedules {
+			schedule := v.(map[string]interface{})
+			start := schedule["start"].(string)
+			if start != "" && start == schedule["end"].(string) {
+				return fmt.Errorf("policy_options.0.time_box.0.schedule.%d: the end time must differ from the start time, got %q", i, start)
+			}
+		}
+	}
+
+	if len(rateLimits) > 0 && rateLimits[0] != nil {
+		rateLimit := rateLimits[0].(map[string]interface{})
+		return checkRateLimitTimeFrame(rateLimit["applies_to"].(string), rateLimit["time_frame"].(string))
+	}
+	return nil
+}
+
+// customRuleSetNames returns the sorted names of the rule blocks of a set
+func customRuleSetNames(rules []interface{}) []string {
+	names := make([]string, 0, len(rules))
+	for _, v := range rules {
+		if rule, ok := v.(map[string]interface{}); ok {
+			names = append(names, rule["name"].(string))
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// expandCustomRuleSetEntry converts a rule block of a set into a custom rule
+func expandCustomRuleSetEntry(rule map[string]interface{}) (dd.CustomRule, error) {
+	policyOptions, err := expandPolicyOptions(rule["policy_options"].([]interface{}))
+	if err != nil {
+		return dd.CustomRule{}, err
+	}
+	enabled := rule["enabled"].(bool)
+
+	return dd.CustomRule{
+		Name:          rule["name"].(string),
+		Response:      rule["response"].(string),
+		Query:         rule["query"].(string),
+		EndpointType:  rule["endpoint_type"].(string),
+		Priority:      rule["priority"].(string),
+		Enabled:       &enabled,
+		PolicyOptions: policyOptions,
+	}, nil
+}
+
+// flattenCustomRuleSetEntry converts a custom rule into a rule block of a set, keeping the schedules of the previous block
+func flattenCustomRuleSetEntry(customRule dd.CustomRule, previous map[string]interface{}) map[string]interface{} {
+	var schedules []interface{}
+	if policyOptions, ok := previous["policy_options"].([]interface{}); ok && len(policyOptions) > 0 && policyOptions[0] != nil {
+		if timeBoxes := policyOptions[0].(map[string]interface{})["time_box"].([]interface{}); len(timeBoxes) > 0 && timeBoxes[0] != nil {
+			schedules = timeBoxes[0].(map[string]interface{})["schedule"].([]interface{})
+		}
+	}
+
+	enabled := true
+	if customRule.Enabled != nil {
+		enabled = *customRule.Enabled
+	}
+
+	return map[string]interface{}{
+		"name":           customRule.Name,
+		"query":          customRule.Query,
+		"response":       customRule.Response,
+		"priority":       customRule.Priority,
+		"endpoint_type":  customRule.EndpointType,
+		"enabled":        enabled,
+		"policy_options": flattenPolicyOptions(customRule.PolicyOptions, schedules),
+	}
+}
+
+// customRuleSetEntryChanged reports whether a custom rule differs from the expanded rule block of a set
+func customRuleSetEntryChanged(existing, desired dd.CustomRule) bool {
+	normalize := func(po *dd.PolicyOptions) *dd.PolicyOptions {
+		if po == nil || po.TimeBox == nil {
+			return po
+		}
+		normalized := *po
+		timeBox := *po.TimeBox
+		timeBox.AuthorizedHoursOfTheWeek = normalizeHoursOfWeek(timeBox.AuthorizedHoursOfTheWeek)
+		normalized.TimeBox = &timeBox
+		return &normalized
+	}
+
+	return existing.Name != desired.Name ||
+		existing.Query != desired.Query ||
+		existing.Response != desired.Response ||
+		existing.EndpointType != desired.EndpointType ||
+		existing.Priority != desired.Priority ||
+		// a custom rule is enabled when the API does not return its state
+		(existing.Enabled == nil || *existing.Enabled) != (desired.Enabled == nil || *desired.Enabled) ||
+		!reflect.DeepEqual(normalize(existing.PolicyOptions), normalize(desired.PolicyOptions))
+}
+
+// expandCustomRuleSetIDs converts the rule_ids map of the state into the IDs of the custom rules by name
+func expandCustomRuleSetIDs(ruleIDs map[string]interface{}) map[string]int {
+	ids := make(map[string]int, len(ruleIDs))
+	for name, v := range ruleIDs {
+		id, err := strconv.Atoi(v.(string))
+		if err != nil {
+			log.Printf("[WARN] ignoring the invalid ID %q of custom rule %q", v, name)
+			continue
+		}
+		ids[name] = id
+	}
+	return ids
+}
+
+// flattenCustomRuleSetIDs converts the IDs of the custom rules by name into the rule_ids map of the state
+func flattenCustomRuleSetIDs(ids map[string]int) map[string]interface{} {
+	ruleIDs := make(map[string]interface{}, len(ids))
+	for name, id := range ids {
+		ruleIDs[name] = strconv.Itoa(id)
+	}
+	return ruleIDs
+}
+
+// resourceCustomRuleSetCreate is used to create the custom rules of a set
+func resourceCustomRuleSetCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	data.SetId(data.Get("name").(string))
+	return resourceCustomRuleSetApply(ctx, data, meta, map[string]int{})
+}
+
+// resourceCustomRuleSetUpdate is used to create, update and delete the custom rules which changed in a set
+func resourceCustomRuleSetUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	previous, _ := data.GetChange("rule_ids")
+	return resourceCustomRuleSetApply(ctx, data, meta, expandCustomRuleSetIDs(previous.(map[string]interface{})))
+}
+
+// resourceCustomRuleSetApply reconciles the custom rules of a set with a single list request,
+// then only creates, updates and deletes the custom rules which differ from the rule blocks.
+// A failure on a rule does not stop the others, and is reported with its name.
+func resourceCustomRuleSetApply(ctx context.Context, data *schema.ResourceData, meta interface{}, ids map[string]int) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	var diags diag.Diagnostics
+
+	existing, err := listCustomRulesByID(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	failed := map[string]bool{}
+	// the path of a diagnostic cannot designate a block of a set, so the rule is named in the summary and the detail
+	ruleError := func(name string, summary string, err error) {
+		failed[name] = true
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       summary,
+			Detail:        fmt.Sprintf("rule %q: %s", name, err),
+			AttributePath: cty.GetAttrPath("rule"),
+		})
+	}
+
+	desired := map[string]bool{}
+	for _, v := range data.Get("rule").(*schema.Set).List() {
+		rule := v.(map[string]interface{})
+		name := rule["name"].(string)
+		desired[name] = true
+
+		customRule, err := expandCustomRuleSetEntry(rule)
+		if err != nil {
+			ruleError(name, fmt.Sprintf("Invalid custom rule %q", name), err)
+			continue
+		}
+
+		id, tracked := ids[name]
+		current, exists := existing[id]
+		switch {
+		case tracked && exists:
+			if !customRuleSetEntryChanged(current, customRule) {
+				continue
+			}
+			customRule.ID = &id
+			if _, err = c.Update(ctx, customRule); err != nil {
+				ruleError(name, fmt.Sprintf("Failed to update custom rule %q", name), err)
+				continue
+			}
+			log.Printf("[DEBUG] updated custom rule %d of set %q", id, data.Id())
+		default:
+			newID, err := c.Create(ctx, customRule)
+			if err != nil {
+				delete(ids, name)
+				ruleError(name, fmt.Sprintf("Failed to create custom rule %q", name), err)
+				continue
+			}
+			ids[name] = *newID
+			log.Printf("[DEBUG] created custom rule %d of set %q", *newID, data.Id())
+		}
+	}
+
+	for name, id := range ids {
+		if desired[name] {
+			continue
+		}
+		if _, exists := existing[id]; exists {
+			if err = c.Delete(ctx, id); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       fmt.Sprintf("Failed to delete custom rule %q", name),
+					Detail:        err.Error(),
+					AttributePath: cty.GetAttrPath("rule_ids").IndexString(name),
+				})
+				continue
+			}
+			log.Printf("[DEBUG] deleted custom rule %d of set %q", id, data.Id())
+		}
+		delete(ids, name)
+	}
+
+	// the IDs are saved even on failure, so the created custom rules are not created again
+	if err = data.Set("rule_ids", flattenCustomRuleSetIDs(ids)); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+	if diags.HasError() {
+		// the failed rule blocks are reverted to the state, so they are planned again
+		if err = data.Set("rule", revertCustomRuleSetEntries(data, failed)); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+		return diags
+	}
+
+	return resourceCustomRuleSetRead(ctx, data, meta)
+}
+
+// listCustomRulesByID fetches all the custom rules with a single list request, indexed by ID
+func listCustomRulesByID(ctx context.Context, c common.API[dd.CustomRule, int]) (map[int]dd.CustomRule, error) {
+	customRules, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[int]dd.CustomRule, len(customRules))
+	for _, customRule := range customRules {
+		if customRule.ID != nil {
+			existing[*customRule.ID] = customRule
+		}
+	}
+	return existing, nil
+}
+
+// revertCustomRuleSetEntries returns the rule blocks of the plan, in which the failed blocks are replaced by their previous value,
+// or removed when they did not exist before
+func revertCustomRuleSetEntries(data *schema.ResourceData, failed map[string]bool) []interface{} {
+	oldRules, newRules := data.GetChange("rule")
+
+	previous := map[string]interface{}{}
+	for _, v := range oldRules.(*schema.Set).List() {
+		rule := v.(map[string]interface{})
+		previous[rule["name"].(string)] = rule
+	}
+
+	var rules []interface{}
+	for _, v := range newRules.(*schema.Set).List() {
+		rule := v.(map[string]interface{})
+		name := rule["name"].(string)
+		switch {
+		case !failed[name]:
+			rules = append(rules, rule)
+		case previous[name] != nil:
+			rules = append(rules, previous[name])
+		}
+	}
+	return rules
+}
+
+// resourceCustomRuleSetRead is used to fetch the custom rules of a set with a single list request.
+// The rule blocks are matched to the custom rules by name, and the blocks of deleted custom rules are removed.
+func resourceCustomRuleSetRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	var diags diag.Diagnostics
+
+	existing, err := listCustomRulesByID(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ids := expandCustomRuleSetIDs(data.Get("rule_ids").(map[string]interface{}))
+	for name, id := range ids {
+		if _, exists := existing[id]; !exists {
+			log.Printf("[WARN] custom rule %d of set %q not found, removing it from the state", id, data.Id())
+			delete(ids, name)
+		}
+	}
+
+	var rules []interface{}
+	for _, v := range data.Get("rule").(*schema.Set).List() {
+		previous := v.(map[string]interface{})
+		id, ok := ids[previous["name"].(string)]
+		if !ok {
+			continue
+		}
+		// the custom rule stays tracked by the name of its block when it has been renamed outside of Terraform,
+		// so the next apply renames it back
+		rules = append(rules, flattenCustomRuleSetEntry(existing[id], previous))
+	}
+
+	if len(ids) == 0 && data.Get("rule").(*schema.Set).Len() > 0 {
+		log.Printf("[WARN] no custom rule of set %q found, removing it from the state", data.Id())
+		data.SetId("")
+		return diags
+	}
+
+	if err = data.Set("rule", rules); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set("rule_ids", flattenCustomRuleSetIDs(ids)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// resourceCustomRuleSetDelete is used to delete all the custom rules of a set
+func resourceCustomRuleSetDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	var diags diag.Diagnostics
+
+	ids := expandCustomRuleSetIDs(data.Get("rule_ids").(map[string]interface{}))
+	for name, id := range ids {
+		if err := c.Delete(ctx, id); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Failed to delete custom rule %q", name),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		delete(ids, name)
+	}
+
+	if diags.HasError() {
+		// the custom rules already deleted are removed from the state
+		if err := data.Set("rule_ids", flattenCustomRuleSetIDs(ids)); err != nil {
+			log.Printf("[ERROR] cannot save the custom rules of set %q: %s", data.Id(), err)
+		}
+	}
+	return diags
+}
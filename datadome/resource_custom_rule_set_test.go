@@ -0,0 +1,111 @@
+package datadome
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomRuleSetEntryChanged(t *testing.T) {
+	enabled, disabled := true, false
+	existing := dd.CustomRule{
+		Name:     "rule",
+		Query:    "ip:1.1.1.1",
+		Response: "allow",
+		Priority: "high",
+		PolicyOptions: &dd.PolicyOptions{
+			TimeBox: &dd.TimeBoxOptions{AuthorizedHoursOfTheWeek: []int{3, 1, 2}, ResponseOutsideTimeBox: "block"},
+		},
+	}
+	desired := existing
+	desired.Enabled = &enabled
+	desired.PolicyOptions = &dd.PolicyOptions{
+		TimeBox: &dd.TimeBoxOptions{AuthorizedHoursOfTheWeek: []int{1, 2, 3}, ResponseOutsideTimeBox: "block"},
+	}
+
+	assert.False(t, customRuleSetEntryChanged(existing, desired))
+
+	changed := desired
+	changed.Query = "ip:2.2.2.2"
+	assert.True(t, customRuleSetEntryChanged(existing, changed))
+
+	changed = desired
+	changed.Enabled = &disabled
+	assert.True(t, customRuleSetEntryChanged(existing, changed))
+
+	changed = desired
+	changed.PolicyOptions = nil
+	assert.True(t, customRuleSetEntryChanged(existing, changed))
+}
+
+func TestCheckCustomRuleSetPolicyOptions(t *testing.T) {
+	timeBox := func(hours []interface{}, schedules []interface{}) []interface{} {
+		return []interface{}{map[string]interface{}{
+			"time_box": []interface{}{map[string]interface{}{
+				"authorized_hours_of_the_week": schema.NewSet(schema.HashInt, hours),
+				"schedule":                     schedules,
+			}},
+			"rate_limit": []interface{}{},
+		}}
+	}
+
+	assert.NoError(t, checkCustomRuleSetPolicyOptions(nil, "block"))
+	assert.NoError(t, checkCustomRuleSetPolicyOptions(timeBox([]interface{}{1, 2}, nil), "allow"))
+
+	assert.EqualError(t, checkCustomRuleSetPolicyOptions(timeBox([]interface{}{1}, nil), "block"),
+		`policy_options is only allowed when response is "allow"`)
+	assert.EqualError(t, checkCustomRuleSetPolicyOptions(timeBox(nil, nil), "allow"),
+		"policy_options.0.time_box: exactly one of authorized_hours_of_the_week or schedule must be specified")
+	assert.EqualError(t, checkCustomRuleSetPolicyOptions(timeBox(nil, []interface{}{
+		map[string]interface{}{"start": "Mon 08:00", "end": "Mon 08:00"},
+	}), "allow"), `policy_options.0.time_box.0.schedule.0: the end time must differ from the start time, got "Mon 08:00"`)
+	assert.EqualError(t, checkCustomRuleSetPolicyOptions([]interface{}{map[string]interface{}{
+		"time_box":   []interface{}{},
+		"rate_limit": []interface{}{},
+	}}, "allow"), "policy_options: exactly one of time_box or rate_limit must be specified")
+}
+
+func TestCustomRuleSetIDs(t *testing.T) {
+	ids := expandCustomRuleSetIDs(map[string]interface{}{"a": "12", "b": "invalid"})
+	assert.Equal(t, map[string]int{"a": 12}, ids)
+	assert.Equal(t, map[string]interface{}{"a": "12"}, flattenCustomRuleSetIDs(ids))
+
+	assert.Equal(t, []string{"a", "b"}, customRuleSetNames([]interface{}{
+		map[string]interface{}{"name": "b"},
+		nil,
+		map[string]interface{}{"name": "a"},
+	}))
+}
+
+func TestResourceCustomRuleSetCreate_ruleError(t *testing.T) {
+	customRules := dd.NewMockClientCustomRule()
+	mockClient := dd.NewMockClientCustomRule()
+	mockClient.ListFunc = customRules.List
+	mockClient.CreateFunc = func(ctx context.Context, params dd.CustomRule) (*int, error) {
+		if params.Name == "partner-b" {
+			return nil, errors.New("invalid query")
+		}
+		return customRules.Create(ctx, params)
+	}
+
+	data := schema.TestResourceDataRaw(t, resourceCustomRuleSet().Schema, map[string]interface{}{
+		"name": "partners",
+		"rule": []interface{}{
+			map[string]interface{}{"name": "partner-a", "query": "ip:1.1.1.1", "response": "allow"},
+			map[string]interface{}{"name": "partner-b", "query": "ip:2.2.2.2", "response": "allow"},
+		},
+	})
+	diags := resourceCustomRuleSetCreate(context.Background(), data, &ProviderConfig{ClientCustomRule: mockClient})
+
+	if assert.Len(t, diags, 1) {
+		assert.Equal(t, `Failed to create custom rule "partner-b"`, diags[0].Summary)
+		assert.Equal(t, `rule "partner-b": invalid query`, diags[0].Detail)
+	}
+	assert.Equal(t, []string{"partner-a"}, customRuleSetNames(data.Get("rule").(*schema.Set).List()))
+	assert.Contains(t, data.Get("rule_ids"), "partner-a")
+	assert.NotContains(t, data.Get("rule_ids"), "partner-b")
+}
@@ -3,6 +3,7 @@ package datadome
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -23,7 +24,15 @@ func resourceEndpoint() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"name", "name_prefix"},
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"name_prefix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
 				ValidateFunc: validation.StringIsNotEmpty,
 			},
 			"description": {
@@ -62,28 +71,28 @@ func resourceEndpoint() *schema.Resource {
 				Default:      "Lax",
 			},
 			"domain": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringIsValidRegExp,
-				AtLeastOneOf: []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"},
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateEndpointRegex,
+				AtLeastOneOf:     []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"},
 			},
 			"path_inclusion": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringIsValidRegExp,
-				AtLeastOneOf: []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"},
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateEndpointRegex,
+				AtLeastOneOf:     []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"},
 			},
 			"path_exclusion": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringIsValidRegExp,
-				AtLeastOneOf: []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"},
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateEndpointRegex,
+				AtLeastOneOf:     []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"},
 			},
 			"user_agent_inclusion": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringIsValidRegExp,
-				AtLeastOneOf: []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"},
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validateEndpointRegex,
+				AtLeastOneOf:     []string{"domain", "path_inclusion", "path_exclusion", "user_agent_inclusion", "query"},
 			},
 			"query": {
 				Type:         schema.TypeString,
@@ -107,9 +116,18 @@ func resourceEndpoint() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"test_case": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     endpointTestCaseSchema,
+			},
 		},
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceEndpointImport,
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(1 * time.Minute),
@@ -126,6 +144,10 @@ func resourceEndpoint() *schema.Resource {
 // - the "traffic_usage" value does not fit with the "source" value
 // - the "protection_enabled" is set to `true` and the "detection_enabled" is set to `false`
 // - the "query" field is not empty and one of "domain", "path_inclusion", "path_exclusion", or "user_agent_inclusion" is not empty either
+// - a "test_case" fails against the planned regular expressions
+// - the endpoint is shadowed by, or shadows, another endpoint and the provider enables strict_plan_checks
+// - the endpoint adopted with "adopt_existing" has another "source" or "traffic_usage" and the provider enables strict_plan_checks
+// - the "name" is already used by another endpoint of the account
 func customizeDiffEndpoints(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
 	source := data.Get("source").(string)
 	trafficUsage := data.Get("traffic_usage").(string)
@@ -158,7 +180,61 @@ func customizeDiffEndpoints(ctx context.Context, data *schema.ResourceDiff, meta
 		return fmt.Errorf(`"query" must be empty if whether "domain", "path_inclusion", "path_exclusion", or "user_agent_inclusion" is filled`)
 	}
 
-	return nil
+	if err := checkEndpointTestCases(ctx, data); err != nil {
+		return err
+	}
+
+	// the checks calling the API share a single list of the endpoints
+	list := listOnce(ctx, meta.(*ProviderConfig).ClientEndpoint)
+	if err := checkEndpointShadowing(ctx, data, meta, list); err != nil {
+		return err
+	}
+
+	if err := checkAdoptedEndpoint(ctx, data, meta, list); err != nil {
+		return err
+	}
+
+	return checkNameAvailable(data, list, "endpoint", func(endpoint dd.Endpoint) (string, string) {
+		if endpoint.ID == nil {
+			return endpoint.Name, ""
+		}
+		return endpoint.Name, *endpoint.ID
+	})
+}
+
+// checkAdoptedEndpoint reports when the endpoint adopted on creation has another source or traffic usage than the configuration,
+// as the apply changes them and so the traffic routed to the endpoint
+func checkAdoptedEndpoint(ctx context.Context, data *schema.ResourceDiff, meta interface{}, list func() ([]dd.Endpoint, error)) error {
+	if data.Id() != "" || !data.Get("adopt_existing").(bool) {
+		return nil
+	}
+	for _, field := range []string{"name", "source", "traffic_usage"} {
+		if !data.NewValueKnown(field) {
+			return nil
+		}
+	}
+
+	name := data.Get("name").(string)
+	endpoints, err := list()
+	if err != nil {
+		log.Printf("[WARN] unable to list endpoints to check the endpoint %q to adopt: %s", name, err)
+		return nil
+	}
+
+	source := data.Get("source").(string)
+	trafficUsage := data.Get("traffic_usage").(string)
+	var findings []planFinding
+	for _, endpoint := range endpoints {
+		if endpoint.Name != name || (endpoint.Source == source && endpoint.TrafficUsage == trafficUsage) {
+			continue
+		}
+		findings = append(findings, planFinding{
+			Summary: "Adopted endpoint with another routing",
+			Detail: fmt.Sprintf("The endpoint %q with the ID %s has the source %q and the traffic usage %q, the apply changes them to %q and %q.",
+				name, endpointIDOf(endpoint), endpoint.Source, endpoint.TrafficUsage, source, trafficUsage),
+		})
+	}
+	return reportPlanFindings(ctx, meta, findings)
 }
 
 // resourceCustomRuleCreate is used to create new custom rule
@@ -175,7 +251,7 @@ func resourceEndpointCreate(ctx context.Context, data *schema.ResourceData, meta
 	query := common.GetOptionalValueWithoutZeroValue[string](data, "query")
 
 	newEndpoint := dd.Endpoint{
-		Name:               data.Get("name").(string),
+		Name:               resolveResourceName(data),
 		Description:        description,
 		PositionBefore:     positionBefore,
 		TrafficUsage:       data.Get("traffic_usage").(string),
@@ -191,6 +267,22 @@ func resourceEndpointCreate(ctx context.Context, data *schema.ResourceData, meta
 		ProtectionEnabled:  data.Get("protection_enabled").(bool),
 	}
 
+	if data.Get("adopt_existing").(bool) {
+		existing, err := findEndpointByName(ctx, c, newEndpoint.Name)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if existing != nil {
+			log.Printf("[INFO] adopting existing endpoint %q with ID %s", existing.Name, *existing.ID)
+			newEndpoint.ID = existing.ID
+			if _, err = c.Update(ctx, newEndpoint); err != nil {
+				return diag.FromErr(err)
+			}
+			data.SetId(*existing.ID)
+			return resourceEndpointRead(ctx, data, meta)
+		}
+	}
+
 	id, err := c.Create(ctx, newEndpoint)
 	if err != nil {
 		return diag.FromErr(err)
@@ -201,6 +293,55 @@ func resourceEndpointCreate(ctx context.Context, data *schema.ResourceData, meta
 	return resourceEndpointRead(ctx, data, meta)
 }
 
+// findEndpointByName returns the endpoint with the given name, or nil when none exists.
+// It raises an error when several endpoints share the same name.
+func findEndpointByName(ctx context.Context, c common.API[dd.Endpoint, string], name string) (*dd.Endpoint, error) {
+	endpoints, err := c.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*dd.Endpoint
+	for i := range endpoints {
+		if endpoints[i].Name == name {
+			matches = append(matches, &endpoints[i])
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = *m.ID
+		}
+		return nil, fmt.Errorf("multiple endpoints are named %q (IDs: %s)", name, strings.Join(ids, ", "))
+	}
+}
+
+// resourceEndpointImport is used to import an endpoint by its ID, or by its name with the "name:<endpoint name>" syntax
+func resourceEndpointImport(ctx context.Context, data *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name, ok := strings.CutPrefix(data.Id(), importByNamePrefix)
+	if !ok {
+		return []*schema.ResourceData{data}, nil
+	}
+
+	config := meta.(*ProviderConfig)
+	endpoint, err := findEndpointByName(ctx, config.ClientEndpoint, name)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, fmt.Errorf("no endpoint found with name %q", name)
+	}
+	data.SetId(*endpoint.ID)
+
+	return []*schema.ResourceData{data}, nil
+}
+
 // resourceCustomRuleRead is used to fetch the custom rule by its ID
 func resourceEndpointRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	config := meta.(*ProviderConfig)
@@ -0,0 +1,598 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/netip"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// ipRuleSetField is the field of the queries of the custom rules of an IP rule set
+const ipRuleSetField = "ip"
+
+// ipRuleSetDefaultMaxQueryLength is the default maximum length of the query of each custom rule of an IP rule set
+const ipRuleSetDefaultMaxQueryLength = 4000
+
+// resourceIPRuleSet define the CRUD operations and the schema definition for sets of IP addresses and CIDR ranges,
+// sharded across as many DataDome custom rules as needed to keep their queries short enough.
+func resourceIPRuleSet() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceIPRuleSetCreate,
+		ReadContext:   resourceIPRuleSetRead,
+		UpdateContext: resourceIPRuleSetUpdate,
+		DeleteContext: resourceIPRuleSetDelete,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"ips": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIPOrCIDR,
+				},
+			},
+			"response": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(customRuleResponses, false),
+			},
+			"priority": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(customRulePriorities, false),
+				Default:      "high",
+			},
+			"endpoint_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(customRuleEndpointTypes, false),
+			},
+			"max_query_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      ipRuleSetDefaultMaxQueryLength,
+				ValidateFunc: validation.IntAtLeast(64),
+			},
+			"cidrs": {
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"shard": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cidrs": {
+							Type:     schema.TypeSet,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Read:   schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffIPRuleSet,
+	}
+}
+
+// validateIPOrCIDR checks that the value is an IP address or a CIDR range
+func validateIPOrCIDR(v interface{}, k string) (ws []string, errors []error) {
+	value, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %q to be string", k)}
+	}
+	if _, err := parseIPPrefix(value); err != nil {
+		return nil, []error{fmt.Errorf("expected %q to be an IP address or a CIDR range: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// expandStringSet converts a set of strings into a sorted slice
+func expandStringSet(set *schema.Set) []string {
+	values := make([]string, 0, set.Len())
+	for _, v := range set.List() {
+		values = append(values, v.(string))
+	}
+	slices.Sort(values)
+	return values
+}
+
+// customizeDiffIPRuleSet checks that the names of the shards are available, plans the aggregated CIDR ranges,
+// and marks the shards as unknown when they need to be updated
+func customizeDiffIPRuleSet(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
+	if err := checkIPRuleShardNamesAvailable(ctx, data, meta); err != nil {
+		return err
+	}
+
+	if rawConfig := data.GetRawConfig(); !rawConfig.IsNull() && !rawConfig.GetAttr("ips").IsWhollyKnown() {
+		if err := data.SetNewComputed("cidrs"); err != nil {
+			return err
+		}
+		return data.SetNewComputed("shard")
+	}
+
+	cidrs, err := aggregateIPs(expandStringSet(data.Get("ips").(*schema.Set)))
+	if err != nil {
+		return err
+	}
+
+	previous := expandStringSet(data.Get("cidrs").(*schema.Set))
+	if sorted := slices.Sorted(slices.Values(cidrs)); !slices.Equal(previous, sorted) {
+		if err = data.SetNew("cidrs", cidrs); err != nil {
+			return err
+		}
+		return data.SetNewComputed("shard")
+	}
+
+	if data.HasChanges("name", "response", "priority", "endpoint_type", "max_query_length") {
+		return data.SetNewComputed("shard")
+	}
+	return nil
+}
+
+// ipRuleShard is a custom rule of an IP rule set, named after the set and its number
+type ipRuleShard struct {
+	ID     *int
+	Number int
+	CIDRs  []string
+}
+
+// ipRuleShardName returns the name of the custom rule of a shard
+func ipRuleShardName(name string, number int) string {
+	return fmt.Sprintf("%s-%d", name, number)
+}
+
+// ipRuleShardNameRegexp returns the regular expression matching the names of the custom rules of the shards of a set
+func ipRuleShardNameRegexp(name string) *regexp.Regexp {
+	return regexp.MustCompile(`^` + regexp.QuoteMeta(name) + `-[1-9][0-9]*$`)
+}
+
+// checkIPRuleShardNamesAvailable raises an error when a custom rule of the account, other than the shards of the set,
+// is named like a shard "<name>-<number>", as it would clash with a new shard and be taken for a shard on import.
+// The check only runs when the name is set or changed.
+func checkIPRuleShardNamesAvailable(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
+	if data.Id() != "" && !data.HasChange("name") {
+		return nil
+	}
+	if !data.NewValueKnown("name") {
+		return nil
+	}
+	name := data.Get("name").(string)
+	if name == "" {
+		return nil
+	}
+
+	customRules, err := meta.(*ProviderConfig).ClientCustomRule.List(ctx)
+	if err != nil {
+		log.Printf("[WARN] unable to list the custom rules to check that the shard names of %q are available: %s", name, err)
+		return nil
+	}
+
+	previous, _ := data.GetChange("shard")
+	own := map[int]bool{}
+	for _, shard := range expandIPRuleShards(previous.([]interface{})) {
+		own[*shard.ID] = true
+	}
+
+	shardNameRegexp := ipRuleShardNameRegexp(name)
+	for _, customRule := range customRules {
+		if customRule.ID != nil && !own[*customRule.ID] && shardNameRegexp.MatchString(customRule.Name) {
+			return fmt.Errorf("name: the shards of %q would be named like the custom rule %q with the ID %d, choose another name", name, customRule.Name, *customRule.ID)
+		}
+	}
+	return nil
+}
+
+// ipRuleShardNumber returns the number of a shard from the name of its custom rule
+func ipRuleShardNumber(shardName string) (int, bool) {
+	i := strings.LastIndexByte(shardName, '-')
+	if i < 0 {
+		return 0, false
+	}
+	number, err := strconv.Atoi(shardName[i+1:])
+	return number, err == nil && number > 0
+}
+
+// ipRuleShardQuery returns the query of a shard, matching any of its CIDR ranges
+func ipRuleShardQuery(cidrs []string) string {
+	return query.Field(ipRuleSetField).In(cidrs...).String()
+}
+
+// ipRuleShardQueryLength returns the length of the query of a shard from the total length of its formatted values,
+// which is faster than formatting the query for each assignment
+func ipRuleShardQueryLength(count, valuesLength int) int {
+	length := len(ipRuleSetField) + 1 + valuesLength
+	if count > 1 {
+		length += len("()") + len(" OR ")*(count-1)
+	}
+	return length
+}
+
+// ipRuleShardCIDRs returns the IP addresses and CIDR ranges matched by the query of a custom rule
+func ipRuleShardCIDRs(q string) []string {
+	expr, err := query.Parse(q)
+	if err != nil {
+		log.Printf("[WARN] cannot parse the query of an IP rule set shard: %s", err)
+		return nil
+	}
+
+	term, ok := expr.(query.Term)
+	if !ok || term.Field != ipRuleSetField {
+		return nil
+	}
+	cidrs := make([]string, 0, len(term.Values))
+	for _, v := range term.Values {
+		if value, ok := v.Literal(); ok {
+			cidrs = append(cidrs, value)
+		}
+	}
+	return cidrs
+}
+
+// planIPRuleShards assigns the CIDR ranges to shards, keeping the ranges already assigned in the previous shards.
+// New ranges go first to a shard which contained an overlapping range, then to the first shard with enough room,
+// then to new shards, so adding or removing an IP address only updates the shard it belongs to.
+// Empty shards are dropped.
+func planIPRuleShards(previous []ipRuleShard, cidrs []string, maxQueryLength int) []ipRuleShard {
+	rank := make(map[string]int, len(cidrs))
+	for i, cidr := range cidrs {
+		rank[cidr] = i
+	}
+
+	assigned := make(map[string]bool, len(cidrs))
+	shards := make([]ipRuleShard, len(previous))
+	lengths := make([]int, len(previous))
+	previousPrefixes := make([][]netip.Prefix, len(previous))
+	numbers := map[int]bool{}
+	for i, shard := range previous {
+		shards[i] = ipRuleShard{ID: shard.ID, Number: shard.Number}
+		numbers[shard.Number] = true
+		for _, cidr := range shard.CIDRs {
+			if prefix, err := parseIPPrefix(cidr); err == nil {
+				previousPrefixes[i] = append(previousPrefixes[i], prefix)
+			}
+			if _, ok := rank[cidr]; !ok || assigned[cidr] {
+				continue
+			}
+			valueLength := len(query.Literal(cidr).String())
+			if ipRuleShardQueryLength(len(shards[i].CIDRs)+1, lengths[i]+valueLength) > maxQueryLength {
+				// the maximum length has been reduced, the range is moved to another shard
+				continue
+			}
+			shards[i].CIDRs = append(shards[i].CIDRs, cidr)
+			lengths[i] += valueLength
+			assigned[cidr] = true
+		}
+	}
+
+	fits := func(i int, valueLength int) bool {
+		return ipRuleShardQueryLength(len(shards[i].CIDRs)+1, lengths[i]+valueLength) <= maxQueryLength
+	}
+
+	for _, cidr := range cidrs {
+		if assigned[cidr] {
+			continue
+		}
+		valueLength := len(query.Literal(cidr).String())
+		prefix, _ := parseIPPrefix(cidr)
+
+		target := -1
+		for i := range previous {
+			overlaps := slices.ContainsFunc(previousPrefixes[i], prefix.Overlaps)
+			if overlaps && fits(i, valueLength) {
+				target = i
+				break
+			}
+		}
+		for i := 0; target < 0 && i < len(shards); i++ {
+			if fits(i, valueLength) {
+				target = i
+			}
+		}
+		if target < 0 {
+			number := 1
+			for numbers[number] {
+				number++
+			}
+			numbers[number] = true
+			shards = append(shards, ipRuleShard{Number: number})
+			lengths = append(lengths, 0)
+			target = len(shards) - 1
+		}
+
+		shards[target].CIDRs = append(shards[target].CIDRs, cidr)
+		lengths[target] += valueLength
+		assigned[cidr] = true
+	}
+
+	planned := make([]ipRuleShard, 0, len(shards))
+	for _, shard := range shards {
+		if len(shard.CIDRs) == 0 {
+			continue
+		}
+		sort.Slice(shard.CIDRs, func(i, j int) bool { return rank[shard.CIDRs[i]] < rank[shard.CIDRs[j]] })
+		planned = append(planned, shard)
+	}
+	return planned
+}
+
+// expandIPRuleShards converts the shard blocks of the state
+func expandIPRuleShards(shards []interface{}) []ipRuleShard {
+	result := make([]ipRuleShard, 0, len(shards))
+	for _, v := range shards {
+		block := v.(map[string]interface{})
+		id := block["id"].(int)
+		number, _ := ipRuleShardNumber(block["name"].(string))
+		result = append(result, ipRuleShard{
+			ID:     &id,
+			Number: number,
+			CIDRs:  expandStringSet(block["cidrs"].(*schema.Set)),
+		})
+	}
+	return result
+}
+
+// flattenIPRuleShards converts the shards into blocks of the state
+func flattenIPRuleShards(name string, shards []ipRuleShard) []interface{} {
+	result := make([]interface{}, 0, len(shards))
+	for _, shard := range shards {
+		cidrs := make([]interface{}, len(shard.CIDRs))
+		for i, cidr := range shard.CIDRs {
+			cidrs[i] = cidr
+		}
+		result = append(result, map[string]interface{}{
+			"id":    *shard.ID,
+			"name":  ipRuleShardName(name, shard.Number),
+			"cidrs": schema.NewSet(schema.HashString, cidrs),
+		})
+	}
+	return result
+}
+
+// resourceIPRuleSetCreate is used to create the custom rules of an IP rule set
+func resourceIPRuleSetCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	data.SetId(data.Get("name").(string))
+	return resourceIPRuleSetApply(ctx, data, meta, nil)
+}
+
+// resourceIPRuleSetUpdate is used to update the custom rules of the shards which changed
+func resourceIPRuleSetUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	previous, _ := data.GetChange("shard")
+	// the planned shards are unknown, the previous ones are kept in the state until the requests succeed
+	if err := data.Set("shard", previous); err != nil {
+		return diag.FromErr(err)
+	}
+	data.SetId(data.Get("name").(string))
+	return resourceIPRuleSetApply(ctx, data, meta, expandIPRuleShards(previous.([]interface{})))
+}
+
+// resourceIPRuleSetApply creates, updates and deletes the custom rules of the shards.
+// The shards are saved in the state after each request, so a failure does not lose track of the created custom rules.
+func resourceIPRuleSetApply(ctx context.Context, data *schema.ResourceData, meta interface{}, previous []ipRuleShard) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	cidrs, err := aggregateIPs(expandStringSet(data.Get("ips").(*schema.Set)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	name := data.Get("name").(string)
+	planned := planIPRuleShards(previous, cidrs, data.Get("max_query_length").(int))
+	attributesChanged := data.HasChanges("name", "response", "priority", "endpoint_type")
+
+	current := slices.Clone(previous)
+	save := func() error {
+		return data.Set("shard", flattenIPRuleShards(name, current))
+	}
+	fail := func(shard ipRuleShard, action string, err error) diag.Diagnostics {
+		if saveErr := save(); saveErr != nil {
+			log.Printf("[ERROR] cannot save the shards of IP rule set %q: %s", name, saveErr)
+		}
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Failed to %s custom rule %q", action, ipRuleShardName(name, shard.Number)),
+			Detail:        err.Error(),
+			AttributePath: cty.GetAttrPath("shard"),
+		}}
+	}
+
+	for p := range planned {
+		shard := planned[p]
+		customRule := dd.CustomRule{
+			ID:           shard.ID,
+			Name:         ipRuleShardName(name, shard.Number),
+			Response:     data.Get("response").(string),
+			Query:        ipRuleShardQuery(shard.CIDRs),
+			EndpointType: data.Get("endpoint_type").(string),
+			Priority:     data.Get("priority").(string),
+		}
+
+		if shard.ID == nil {
+			id, err := c.Create(ctx, customRule)
+			if err != nil {
+				return fail(shard, "create", err)
+			}
+			planned[p].ID = id
+			current = append(current, planned[p])
+			log.Printf("[DEBUG] created custom rule %d for shard %d of IP rule set %q", *id, shard.Number, name)
+			continue
+		}
+
+		i := slices.IndexFunc(current, func(s ipRuleShard) bool { return *s.ID == *shard.ID })
+		if !attributesChanged && slices.Equal(slices.Sorted(slices.Values(current[i].CIDRs)), slices.Sorted(slices.Values(shard.CIDRs))) && current[i].Number == shard.Number {
+			continue
+		}
+		if _, err = c.Update(ctx, customRule); err != nil {
+			return fail(shard, "update", err)
+		}
+		current[i] = shard
+		log.Printf("[DEBUG] updated custom rule %d for shard %d of IP rule set %q", *shard.ID, shard.Number, name)
+	}
+
+	for _, shard := range previous {
+		if slices.ContainsFunc(planned, func(s ipRuleShard) bool { return *s.ID == *shard.ID }) {
+			continue
+		}
+		if err = c.Delete(ctx, *shard.ID); err != nil {
+			return fail(shard, "delete", err)
+		}
+		current = slices.DeleteFunc(current, func(s ipRuleShard) bool { return *s.ID == *shard.ID })
+		log.Printf("[DEBUG] deleted custom rule %d of shard %d of IP rule set %q", *shard.ID, shard.Number, name)
+	}
+
+	// the shards are saved in the planned order
+	current = planned
+	if err = save(); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceIPRuleSetRead(ctx, data, meta)
+}
+
+// resourceIPRuleSetRead is used to fetch the custom rules of the shards with a single list request.
+// When no shard is known, such as after an import, the custom rules named after the set are used.
+func resourceIPRuleSetRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	var diags diag.Diagnostics
+
+	name := data.Id()
+	customRules, err := c.List(ctx)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	shards := expandIPRuleShards(data.Get("shard").([]interface{}))
+	if len(shards) == 0 {
+		shardNameRegexp := ipRuleShardNameRegexp(name)
+		for _, customRule := range customRules {
+			if customRule.ID != nil && shardNameRegexp.MatchString(customRule.Name) {
+				number, _ := ipRuleShardNumber(customRule.Name)
+				shards = append(shards, ipRuleShard{ID: customRule.ID, Number: number})
+			}
+		}
+		sort.Slice(shards, func(i, j int) bool { return shards[i].Number < shards[j].Number })
+	}
+
+	var found []ipRuleShard
+	var cidrs []interface{}
+	for _, shard := range shards {
+		i := slices.IndexFunc(customRules, func(r dd.CustomRule) bool { return r.ID != nil && *r.ID == *shard.ID })
+		if i < 0 {
+			log.Printf("[WARN] custom rule %d of IP rule set %q not found, removing it from the state", *shard.ID, name)
+			continue
+		}
+		customRule := customRules[i]
+
+		shard.CIDRs = ipRuleShardCIDRs(customRule.Query)
+		for _, cidr := range shard.CIDRs {
+			cidrs = append(cidrs, cidr)
+		}
+		found = append(found, shard)
+
+		// the attributes of the set are updated when a custom rule has drifted, so the plan updates all of them
+		for key, value := range map[string]string{
+			"response":      customRule.Response,
+			"priority":      customRule.Priority,
+			"endpoint_type": customRule.EndpointType,
+		} {
+			if data.Get(key).(string) != value {
+				if err = data.Set(key, value); err != nil {
+					return diag.FromErr(err)
+				}
+			}
+		}
+	}
+
+	if len(found) == 0 {
+		log.Printf("[WARN] no custom rule of IP rule set %q found, removing it from the state", name)
+		data.SetId("")
+		return diags
+	}
+
+	// the arguments which cannot be read from the custom rules are initialized after an import
+	if _, ok := data.GetOk("name"); !ok {
+		if err = data.Set("name", name); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if _, ok := data.GetOk("max_query_length"); !ok {
+		if err = data.Set("max_query_length", ipRuleSetDefaultMaxQueryLength); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if data.Get("ips").(*schema.Set).Len() == 0 {
+		if err = data.Set("ips", schema.NewSet(schema.HashString, cidrs)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+	if err = data.Set("cidrs", schema.NewSet(schema.HashString, cidrs)); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set("shard", flattenIPRuleShards(name, found)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// resourceIPRuleSetDelete is used to delete the custom rules of all the shards
+func resourceIPRuleSetDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	var diags diag.Diagnostics
+
+	name := data.Get("name").(string)
+	shards := expandIPRuleShards(data.Get("shard").([]interface{}))
+	for len(shards) > 0 {
+		if err := c.Delete(ctx, *shards[0].ID); err != nil {
+			// the custom rules already deleted are removed from the state
+			if setErr := data.Set("shard", flattenIPRuleShards(name, shards)); setErr != nil {
+				log.Printf("[ERROR] cannot save the shards of IP rule set %q: %s", name, setErr)
+			}
+			return diag.FromErr(err)
+		}
+		shards = shards[1:]
+	}
+
+	return diags
+}
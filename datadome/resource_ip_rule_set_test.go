@@ -0,0 +1,91 @@
+package datadome
+
+import (
+	"testing"
+
+	"github.com/datadome/terraform-provider/datadome-client-go/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanIPRuleShards(t *testing.T) {
+	id1, id2 := 1, 2
+
+	// each shard holds two IPv4 addresses of 8 characters: "ip:(" + 8 + " OR " + 8 + ")"
+	maxQueryLength := 25
+
+	shards := planIPRuleShards(nil, []string{"10.0.0.1", "10.0.0.3", "10.0.0.5"}, maxQueryLength)
+	assert.Equal(t, []ipRuleShard{
+		{Number: 1, CIDRs: []string{"10.0.0.1", "10.0.0.3"}},
+		{Number: 2, CIDRs: []string{"10.0.0.5"}},
+	}, shards)
+
+	previous := []ipRuleShard{
+		{ID: &id1, Number: 1, CIDRs: []string{"10.0.0.1", "10.0.0.3"}},
+		{ID: &id2, Number: 2, CIDRs: []string{"10.0.0.5"}},
+	}
+
+	t.Run("added address", func(t *testing.T) {
+		shards := planIPRuleShards(previous, []string{"10.0.0.1", "10.0.0.3", "10.0.0.5", "10.0.0.7"}, maxQueryLength)
+		assert.Equal(t, []ipRuleShard{
+			{ID: &id1, Number: 1, CIDRs: []string{"10.0.0.1", "10.0.0.3"}},
+			{ID: &id2, Number: 2, CIDRs: []string{"10.0.0.5", "10.0.0.7"}},
+		}, shards)
+	})
+
+	t.Run("removed address", func(t *testing.T) {
+		shards := planIPRuleShards(previous, []string{"10.0.0.3", "10.0.0.5"}, maxQueryLength)
+		assert.Equal(t, []ipRuleShard{
+			{ID: &id1, Number: 1, CIDRs: []string{"10.0.0.3"}},
+			{ID: &id2, Number: 2, CIDRs: []string{"10.0.0.5"}},
+		}, shards)
+	})
+
+	t.Run("emptied shard", func(t *testing.T) {
+		shards := planIPRuleShards(previous, []string{"10.0.0.1", "10.0.0.3"}, maxQueryLength)
+		assert.Equal(t, []ipRuleShard{
+			{ID: &id1, Number: 1, CIDRs: []string{"10.0.0.1", "10.0.0.3"}},
+		}, shards)
+	})
+
+	t.Run("merged range stays in its shard", func(t *testing.T) {
+		shards := planIPRuleShards(previous, []string{"10.0.0.1", "10.0.0.3", "10.0.0.4/31"}, maxQueryLength)
+		assert.Equal(t, []ipRuleShard{
+			{ID: &id1, Number: 1, CIDRs: []string{"10.0.0.1", "10.0.0.3"}},
+			{ID: &id2, Number: 2, CIDRs: []string{"10.0.0.4/31"}},
+		}, shards)
+	})
+
+	t.Run("new shard takes the first free number", func(t *testing.T) {
+		previous := []ipRuleShard{{ID: &id2, Number: 2, CIDRs: []string{"10.0.0.1", "10.0.0.3"}}}
+		shards := planIPRuleShards(previous, []string{"10.0.0.1", "10.0.0.3", "10.0.0.5"}, maxQueryLength)
+		assert.Equal(t, []ipRuleShard{
+			{ID: &id2, Number: 2, CIDRs: []string{"10.0.0.1", "10.0.0.3"}},
+			{Number: 1, CIDRs: []string{"10.0.0.5"}},
+		}, shards)
+	})
+
+	t.Run("reduced maximum length", func(t *testing.T) {
+		shards := planIPRuleShards(previous, []string{"10.0.0.1", "10.0.0.3", "10.0.0.5"}, 15)
+		assert.Equal(t, []ipRuleShard{
+			{ID: &id1, Number: 1, CIDRs: []string{"10.0.0.1"}},
+			{ID: &id2, Number: 2, CIDRs: []string{"10.0.0.5"}},
+			{Number: 3, CIDRs: []string{"10.0.0.3"}},
+		}, shards)
+	})
+}
+
+func TestIPRuleShardQueryLength(t *testing.T) {
+	for _, cidrs := range [][]string{{"10.0.0.1"}, {"10.0.0.0/24", "2001:db8::/32", "192.168.0.1"}} {
+		valuesLength := 0
+		for _, cidr := range cidrs {
+			valuesLength += len(query.Literal(cidr).String())
+		}
+		assert.Equal(t, len(ipRuleShardQuery(cidrs)), ipRuleShardQueryLength(len(cidrs), valuesLength))
+	}
+}
+
+func TestIPRuleShardCIDRs(t *testing.T) {
+	assert.Equal(t, []string{"10.0.0.0/24", "2001:db8::/32"}, ipRuleShardCIDRs(`ip:(10.0.0.0/24 OR "2001:db8::/32")`))
+	assert.Empty(t, ipRuleShardCIDRs("countrycode:FR"))
+	assert.Empty(t, ipRuleShardCIDRs("ip:("))
+}
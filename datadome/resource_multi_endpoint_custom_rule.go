@@ -0,0 +1,380 @@
+package datadome
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// resourceMultiEndpointCustomRule define the CRUD operations and the schema definition for a DataDome custom rule
+// applied to several endpoint types, through one custom rule per endpoint type.
+func resourceMultiEndpointCustomRule() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceMultiEndpointCustomRuleCreate,
+		ReadContext:   resourceMultiEndpointCustomRuleRead,
+		UpdateContext: resourceMultiEndpointCustomRuleUpdate,
+		DeleteContext: resourceMultiEndpointCustomRuleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceMultiEndpointCustomRuleImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"endpoint_types": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(customRuleEndpointTypes, false),
+				},
+			},
+			"query": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"response": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(customRuleSetResponses, false),
+			},
+			"priority": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(customRulePriorities, false),
+				Default:      "high",
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"policy_options": policyOptionsSchema("policy_options.0."),
+			"rule_ids": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Read:   schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		CustomizeDiff: customizeDiffMultiEndpointCustomRule,
+	}
+}
+
+// customizeDiffMultiEndpointCustomRule checks the policy options against the response,
+// and marks the IDs of the custom rules as unknown when endpoint types are added or removed
+func customizeDiffMultiEndpointCustomRule(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
+	if err := checkCustomRuleSetPolicyOptions(data.Get("policy_options").([]interface{}), data.Get("response").(string)); err != nil {
+		return err
+	}
+
+	if err := checkMultiEndpointCustomRuleNamesAvailable(ctx, data, meta); err != nil {
+		return err
+	}
+
+	if data.HasChange("endpoint_types") {
+		return data.SetNewComputed("rule_ids")
+	}
+	return nil
+}
+
+// multiEndpointCustomRuleName returns the name of the custom rule of an endpoint type
+func multiEndpointCustomRuleName(name, endpointType string) string {
+	return fmt.Sprintf("%s-%s", name, endpointType)
+}
+
+// checkMultiEndpointCustomRuleNamesAvailable raises an error when a custom rule of the account, other than the custom rules
+// of the resource, is named like the custom rule "<name>-<endpoint_type>" of a planned endpoint type.
+// The check only runs when the name or the endpoint types are set or changed.
+func checkMultiEndpointCustomRuleNamesAvailable(ctx context.Context, data *schema.ResourceDiff, meta interface{}) error {
+	if data.Id() != "" && !data.HasChanges("name", "endpoint_types") {
+		return nil
+	}
+	if !data.NewValueKnown("name") || !data.NewValueKnown("endpoint_types") {
+		return nil
+	}
+	name := data.Get("name").(string)
+	if name == "" {
+		return nil
+	}
+
+	planned := map[string]string{}
+	for _, endpointType := range expandStringSet(data.Get("endpoint_types").(*schema.Set)) {
+		planned[multiEndpointCustomRuleName(name, endpointType)] = endpointType
+	}
+
+	customRules, err := meta.(*ProviderConfig).ClientCustomRule.List(ctx)
+	if err != nil {
+		log.Printf("[WARN] unable to list the custom rules to check that the names of the custom rules of %q are available: %s", name, err)
+		return nil
+	}
+
+	previous, _ := data.GetChange("rule_ids")
+	own := map[int]bool{}
+	for _, id := range expandCustomRuleSetIDs(previous.(map[string]interface{})) {
+		own[id] = true
+	}
+
+	for _, customRule := range customRules {
+		endpointType, found := planned[customRule.Name]
+		if found && customRule.ID != nil && !own[*customRule.ID] {
+			return fmt.Errorf("name: the custom rule of endpoint type %q would be named %q, which is already used by the custom rule with the ID %d, choose another name", endpointType, customRule.Name, *customRule.ID)
+		}
+	}
+	return nil
+}
+
+// multiEndpointCustomRuleEntry returns the attributes of the custom rule of an endpoint type,
+// in the format of a rule block of a custom rule set
+func multiEndpointCustomRuleEntry(data *schema.ResourceData, endpointType string) map[string]interface{} {
+	return map[string]interface{}{
+		"name":           multiEndpointCustomRuleName(data.Get("name").(string), endpointType),
+		"query":          data.Get("query"),
+		"response":       data.Get("response"),
+		"priority":       data.Get("priority"),
+		"endpoint_type":  endpointType,
+		"enabled":        data.Get("enabled"),
+		"policy_options": data.Get("policy_options"),
+	}
+}
+
+// resourceMultiEndpointCustomRuleCreate is used to create the custom rules of each endpoint type
+func resourceMultiEndpointCustomRuleCreate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	data.SetId(data.Get("name").(string))
+	return resourceMultiEndpointCustomRuleApply(ctx, data, meta, map[string]int{})
+}
+
+// resourceMultiEndpointCustomRuleUpdate is used to create, update and delete the custom rules of the endpoint types
+func resourceMultiEndpointCustomRuleUpdate(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	previous, _ := data.GetChange("rule_ids")
+	if data.HasChange("name") {
+		data.SetId(data.Get("name").(string))
+	}
+	return resourceMultiEndpointCustomRuleApply(ctx, data, meta, expandCustomRuleSetIDs(previous.(map[string]interface{})))
+}
+
+// resourceMultiEndpointCustomRuleApply reconciles the custom rules of the endpoint types with a single list request,
+// then only creates, updates and deletes the custom rules which differ from the configuration.
+// The state is read again afterwards, so the custom rules which failed are planned again.
+func resourceMultiEndpointCustomRuleApply(ctx context.Context, data *schema.ResourceData, meta interface{}, ids map[string]int) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	var diags diag.Diagnostics
+
+	existing, err := listCustomRulesByID(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	endpointTypes := expandStringSet(data.Get("endpoint_types").(*schema.Set))
+	for _, endpointType := range endpointTypes {
+		customRule, err := expandCustomRuleSetEntry(multiEndpointCustomRuleEntry(data, endpointType))
+		if err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+
+		id, tracked := ids[endpointType]
+		current, exists := existing[id]
+		switch {
+		case tracked && exists:
+			if !customRuleSetEntryChanged(current, customRule) {
+				continue
+			}
+			customRule.ID = &id
+			if _, err = c.Update(ctx, customRule); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       fmt.Sprintf("Failed to update custom rule %q", customRule.Name),
+					Detail:        err.Error(),
+					AttributePath: cty.GetAttrPath("rule_ids").IndexString(endpointType),
+				})
+				continue
+			}
+			log.Printf("[DEBUG] updated custom rule %d of %q", id, data.Id())
+		default:
+			newID, err := c.Create(ctx, customRule)
+			if err != nil {
+				delete(ids, endpointType)
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       fmt.Sprintf("Failed to create custom rule %q", customRule.Name),
+					Detail:        err.Error(),
+					AttributePath: cty.GetAttrPath("endpoint_types"),
+				})
+				continue
+			}
+			ids[endpointType] = *newID
+			log.Printf("[DEBUG] created custom rule %d of %q", *newID, data.Id())
+		}
+	}
+
+	for endpointType, id := range ids {
+		if slices.Contains(endpointTypes, endpointType) {
+			continue
+		}
+		if _, exists := existing[id]; exists {
+			if err = c.Delete(ctx, id); err != nil {
+				diags = append(diags, diag.Diagnostic{
+					Severity:      diag.Error,
+					Summary:       fmt.Sprintf("Failed to delete the custom rule of endpoint type %q", endpointType),
+					Detail:        err.Error(),
+					AttributePath: cty.GetAttrPath("rule_ids").IndexString(endpointType),
+				})
+				continue
+			}
+			log.Printf("[DEBUG] deleted custom rule %d of %q", id, data.Id())
+		}
+		delete(ids, endpointType)
+	}
+
+	// the IDs are saved even on failure, so the created custom rules are not created again
+	if err = data.Set("rule_ids", flattenCustomRuleSetIDs(ids)); err != nil {
+		return append(diags, diag.FromErr(err)...)
+	}
+
+	return append(diags, resourceMultiEndpointCustomRuleRead(ctx, data, meta)...)
+}
+
+// resourceMultiEndpointCustomRuleRead is used to fetch the custom rules of the endpoint types with a single list request.
+// The attributes of the first custom rule, in the order of the endpoint types, which differs from the state are saved,
+// so the next apply updates all the custom rules.
+func resourceMultiEndpointCustomRuleRead(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	var diags diag.Diagnostics
+
+	existing, err := listCustomRulesByID(ctx, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	ids := expandCustomRuleSetIDs(data.Get("rule_ids").(map[string]interface{}))
+	for endpointType, id := range ids {
+		if _, exists := existing[id]; !exists {
+			log.Printf("[WARN] custom rule %d of %q not found, removing it from the state", id, data.Id())
+			delete(ids, endpointType)
+		}
+	}
+	if len(ids) == 0 {
+		log.Printf("[WARN] no custom rule of %q found, removing it from the state", data.Id())
+		data.SetId("")
+		return diags
+	}
+
+	var endpointTypes []interface{}
+	drifted := false
+	for _, endpointType := range slices.Sorted(maps.Keys(ids)) {
+		id := ids[endpointType]
+		customRule := existing[id]
+		entry := multiEndpointCustomRuleEntry(data, endpointType)
+
+		// a custom rule renamed or moved to another endpoint type outside of Terraform is left out of the endpoint types,
+		// so the next apply restores it
+		if customRule.Name != entry["name"] || customRule.EndpointType != endpointType {
+			log.Printf("[WARN] custom rule %d of %q has been renamed or moved to another endpoint type", id, data.Id())
+			continue
+		}
+		endpointTypes = append(endpointTypes, endpointType)
+
+		desired, err := expandCustomRuleSetEntry(entry)
+		if drifted || err != nil || !customRuleSetEntryChanged(customRule, desired) {
+			continue
+		}
+		drifted = true
+
+		flattened := flattenCustomRuleSetEntry(customRule, entry)
+		for _, key := range []string{"query", "response", "priority", "enabled", "policy_options"} {
+			if err = data.Set(key, flattened[key]); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+	}
+
+	if err = data.Set("endpoint_types", endpointTypes); err != nil {
+		return diag.FromErr(err)
+	}
+	if err = data.Set("rule_ids", flattenCustomRuleSetIDs(ids)); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return diags
+}
+
+// resourceMultiEndpointCustomRuleImport is used to import the custom rules named "<name>-<endpoint_type>" by their name
+func resourceMultiEndpointCustomRuleImport(ctx context.Context, data *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	name := data.Id()
+
+	customRules, err := meta.(*ProviderConfig).ClientCustomRule.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[string]int{}
+	for _, customRule := range customRules {
+		if customRule.ID == nil || !slices.Contains(customRuleEndpointTypes, customRule.EndpointType) {
+			continue
+		}
+		if customRule.Name == multiEndpointCustomRuleName(name, customRule.EndpointType) {
+			ids[customRule.EndpointType] = *customRule.ID
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no custom rule named %q found", multiEndpointCustomRuleName(name, "<endpoint_type>"))
+	}
+
+	if err = data.Set("name", name); err != nil {
+		return nil, err
+	}
+	if err = data.Set("rule_ids", flattenCustomRuleSetIDs(ids)); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{data}, nil
+}
+
+// resourceMultiEndpointCustomRuleDelete is used to delete the custom rules of all the endpoint types
+func resourceMultiEndpointCustomRuleDelete(ctx context.Context, data *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	config := meta.(*ProviderConfig)
+	c := config.ClientCustomRule
+
+	var diags diag.Diagnostics
+
+	ids := expandCustomRuleSetIDs(data.Get("rule_ids").(map[string]interface{}))
+	for endpointType, id := range ids {
+		if err := c.Delete(ctx, id); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Failed to delete the custom rule of endpoint type %q", endpointType),
+				Detail:   err.Error(),
+			})
+			continue
+		}
+		delete(ids, endpointType)
+	}
+
+	if diags.HasError() {
+		// the custom rules already deleted are removed from the state
+		if err := data.Set("rule_ids", flattenCustomRuleSetIDs(ids)); err != nil {
+			log.Printf("[ERROR] cannot save the custom rules of %q: %s", data.Id(), err)
+		}
+	}
+	return diags
+}
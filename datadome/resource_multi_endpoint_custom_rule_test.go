@@ -0,0 +1,62 @@
+package datadome
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	dd "github.com/datadome/terraform-provider/datadome-client-go"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiEndpointCustomRuleNamesAvailable(t *testing.T) {
+	mockClient := dd.NewMockClientCustomRule()
+	ownID, otherID := 1, 2
+	for _, customRule := range []dd.CustomRule{
+		{ID: &ownID, Name: "partners-web", Query: "ip:1.1.1.1", Response: "allow", EndpointType: "web"},
+		{ID: &otherID, Name: "partners-login", Query: "ip:2.2.2.2", Response: "block", EndpointType: "login"},
+	} {
+		if _, err := mockClient.Create(context.Background(), customRule); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := &ProviderConfig{ClientCustomRule: mockClient}
+	multiEndpointCustomRule := map[string]interface{}{
+		"name":           "partners",
+		"endpoint_types": []interface{}{"web", "login"},
+		"query":          "ip:1.1.1.1",
+		"response":       "allow",
+	}
+
+	resp := testPlanResource(t, config, "datadome_multi_endpoint_custom_rule", multiEndpointCustomRule)
+
+	if assert.Len(t, resp.Diagnostics, 1) {
+		assert.Equal(t, tfprotov5.DiagnosticSeverityError, resp.Diagnostics[0].Severity)
+		assert.Contains(t, resp.Diagnostics[0].Summary, `the custom rule of endpoint type "web" would be named "partners-web", which is already used by the custom rule with the ID 1`)
+	}
+
+	// the custom rules held in the state are not reported
+	data := schema.TestResourceDataRaw(t, resourceMultiEndpointCustomRule().Schema, map[string]interface{}{
+		"name":           "partners",
+		"endpoint_types": []interface{}{"web"},
+		"query":          "ip:1.1.1.1",
+		"response":       "allow",
+	})
+	data.SetId("partners")
+	if err := data.Set("rule_ids", map[string]interface{}{"web": strconv.Itoa(ownID)}); err != nil {
+		t.Fatal(err)
+	}
+	diff := func(config map[string]interface{}) error {
+		_, err := resourceMultiEndpointCustomRule().Diff(context.Background(), data.State(), terraform.NewResourceConfigRaw(config), &ProviderConfig{ClientCustomRule: mockClient})
+		return err
+	}
+
+	multiEndpointCustomRule["endpoint_types"] = []interface{}{"web", "cart"}
+	assert.NoError(t, diff(multiEndpointCustomRule))
+
+	multiEndpointCustomRule["endpoint_types"] = []interface{}{"web", "login"}
+	assert.EqualError(t, diff(multiEndpointCustomRule), `name: the custom rule of endpoint type "login" would be named "partners-login", which is already used by the custom rule with the ID 2, choose another name`)
+}
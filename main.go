@@ -1,15 +1,43 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
+
 	datadome "github.com/datadome/terraform-provider/datadome"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 )
 
 func main() {
-	plugin.Serve(&plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return datadome.Provider()
-		},
-	})
+	var debug bool
+
+	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	// The SDK provider manages the resources, the framework provider serves the provider-defined functions
+	muxServer, err := tf5muxserver.NewMuxServer(ctx,
+		datadome.ProviderServer,
+		providerserver.NewProtocol5(datadome.FrameworkProvider()),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var serveOpts []tf5server.ServeOpt
+	if debug {
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
+	}
+
+	err = tf5server.Serve("registry.terraform.io/datadome/datadome", func() tfprotov5.ProviderServer {
+		return muxServer.ProviderServer()
+	}, serveOpts...)
+	if err != nil {
+		log.Fatal(err)
+	}
 }